@@ -0,0 +1,518 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TangentType is the tangent type of a key, the ".kit" and ".kot" of
+// animCurve.
+type TangentType int
+
+const (
+	TangentGlobal   TangentType = 0
+	TangentFixed    TangentType = 1
+	TangentLinear   TangentType = 2
+	TangentFlat     TangentType = 3
+	TangentStep     TangentType = 5
+	TangentSlow     TangentType = 6
+	TangentFast     TangentType = 7
+	TangentSpline   TangentType = 9
+	TangentClamped  TangentType = 10
+	TangentPlateau  TangentType = 16
+	TangentStepNext TangentType = 17
+	TangentAuto     TangentType = 18
+)
+
+var tangentTypeNames = map[TangentType]string{
+	TangentGlobal:   "global",
+	TangentFixed:    "fixed",
+	TangentLinear:   "linear",
+	TangentFlat:     "flat",
+	TangentStep:     "step",
+	TangentSlow:     "slow",
+	TangentFast:     "fast",
+	TangentSpline:   "spline",
+	TangentClamped:  "clamped",
+	TangentPlateau:  "plateau",
+	TangentStepNext: "stepnext",
+	TangentAuto:     "auto",
+}
+
+func (t TangentType) String() string {
+	if name, ok := tangentTypeNames[t]; ok {
+		return name
+	}
+	return "TangentType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Infinity is the ".pre" and ".pst" of animCurve, the value before the
+// first key and after the last key.
+type Infinity int
+
+const (
+	InfinityConstant      Infinity = 0
+	InfinityLinear        Infinity = 1
+	InfinityCycle         Infinity = 3
+	InfinityCycleRelative Infinity = 4
+	InfinityOscillate     Infinity = 5
+)
+
+var infinityNames = map[Infinity]string{
+	InfinityConstant:      "constant",
+	InfinityLinear:        "linear",
+	InfinityCycle:         "cycle",
+	InfinityCycleRelative: "cycleRelative",
+	InfinityOscillate:     "oscillate",
+}
+
+func (i Infinity) String() string {
+	if name, ok := infinityNames[i]; ok {
+		return name
+	}
+	return "Infinity(" + strconv.Itoa(int(i)) + ")"
+}
+
+// Keyframe is a key of animCurve.
+type Keyframe struct {
+	Time           float64
+	Value          float64
+	InTangentType  TangentType
+	OutTangentType TangentType
+	// InX, InY, OutX and OutY is the tangent of ".kix", ".kiy", ".kox" and
+	// ".koy". X is in seconds for the time input curves and Y is in the
+	// internal units, radians and centimeters, for the angular and linear
+	// output curves. HasInTangent and HasOutTangent reports whether the
+	// file has them.
+	InX           float64
+	InY           float64
+	OutX          float64
+	OutY          float64
+	HasInTangent  bool
+	HasOutTangent bool
+	TanLocked     bool
+	WeightLocked  bool
+	Breakdown     bool
+}
+
+// AnimCurve is an animCurve node like animCurveTL and animCurveUA.
+type AnimCurve struct {
+	Node *Node
+	// Keys is sorted by Time.
+	Keys []*Keyframe
+	// TangentType is the ".tan", the tangent type of the keys without
+	// ".kit" or ".kot".
+	TangentType  TangentType
+	Weighted     bool
+	PreInfinity  Infinity
+	PostInfinity Infinity
+	// FPS converts the tangent x of the time input curves from seconds to
+	// frames. It is 0 for the unitless input curves.
+	FPS float64
+	// YScale converts the tangent y of the angular and linear output curves
+	// from the internal units to the units of the values. It is 0 for the
+	// other curves.
+	YScale float64
+}
+
+// IsTimeInput reports whether the input of c is time like animCurveTL,
+// not a driver like animCurveUL.
+func (c *AnimCurve) IsTimeInput() bool {
+	return strings.HasPrefix(c.Node.GetType(), "animCurveT")
+}
+
+// AsAnimCurve returns the keys of n of animCurve type. The tangent x of
+// the time input curves and the tangent y of the angular and linear
+// output curves are converted by the currentUnit of the scene.
+func (n *Node) AsAnimCurve() (*AnimCurve, error) {
+	typ := n.GetType()
+	if !strings.HasPrefix(typ, "animCurve") {
+		return nil, errors.New(fmt.Sprintf("%s is not an animCurve", n.GetName()))
+	}
+	c := &AnimCurve{Node: n, TangentType: TangentAuto}
+	u := n.object.GetCurrentUnit()
+	if c.IsTimeInput() {
+		fps, err := u.GetFPS()
+		if err != nil {
+			return nil, err
+		}
+		c.FPS = fps
+	}
+	switch typ[len(typ)-1] {
+	case 'A':
+		c.YScale = u.getAngleScale()
+	case 'L':
+		scale, err := u.getLinearScale()
+		if err != nil {
+			return nil, err
+		}
+		c.YScale = scale
+	}
+
+	// The keys may be split to some ".ktv" and the other attributes
+	// refer them by the index.
+	keys := map[int]*Keyframe{}
+	for _, a := range n.Attrs {
+		if a.isDeleted {
+			continue
+		}
+		name, start, err := splitAnimCurveAttr(a.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if name != "ktv" && name != "keyTimeValue" {
+			continue
+		}
+		values := getAttrFloats(a)
+		for i := 0; i+1 < len(values); i += 2 {
+			keys[start+i/2] = &Keyframe{
+				Time:           values[i],
+				Value:          values[i+1],
+				InTangentType:  -1,
+				OutTangentType: -1,
+				TanLocked:      true,
+				WeightLocked:   true,
+			}
+		}
+	}
+	for _, a := range n.Attrs {
+		if a.isDeleted {
+			continue
+		}
+		name, start, _ := splitAnimCurveAttr(a.GetName())
+		values := getAttrFloats(a)
+		if len(values) == 0 {
+			continue
+		}
+		switch name {
+		case "tan", "tangentType":
+			c.TangentType = TangentType(values[0])
+			continue
+		case "wgt", "weightedTangents":
+			c.Weighted = values[0] != 0
+			continue
+		case "pre", "preInfinity":
+			c.PreInfinity = Infinity(values[0])
+			continue
+		case "pst", "postInfinity":
+			c.PostInfinity = Infinity(values[0])
+			continue
+		}
+		for i, v := range values {
+			k, ok := keys[start+i]
+			if !ok {
+				continue
+			}
+			switch name {
+			case "kit", "keyTanInType":
+				k.InTangentType = TangentType(v)
+			case "kot", "keyTanOutType":
+				k.OutTangentType = TangentType(v)
+			case "kix", "keyTanInX":
+				k.InX, k.HasInTangent = v, true
+			case "kiy", "keyTanInY":
+				k.InY, k.HasInTangent = v, true
+			case "kox", "keyTanOutX":
+				k.OutX, k.HasOutTangent = v, true
+			case "koy", "keyTanOutY":
+				k.OutY, k.HasOutTangent = v, true
+			case "kl", "keyTanLocked":
+				k.TanLocked = v != 0
+			case "kwl", "keyWeightLocked":
+				k.WeightLocked = v != 0
+			case "kb", "keyBreakdown":
+				k.Breakdown = v != 0
+			}
+		}
+	}
+
+	indices := make([]int, 0, len(keys))
+	for i := range keys {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	for _, i := range indices {
+		k := keys[i]
+		if k.InTangentType == -1 {
+			k.InTangentType = c.TangentType
+		}
+		if k.OutTangentType == -1 {
+			k.OutTangentType = c.TangentType
+		}
+		c.Keys = append(c.Keys, k)
+	}
+	sort.SliceStable(c.Keys, func(i, j int) bool {
+		return c.Keys[i].Time < c.Keys[j].Time
+	})
+	return c, nil
+}
+
+// Evaluate returns the value of c at t like Maya. The Hermite curve is
+// used for the non-weighted tangents and the Bezier curve for the
+// weighted ones. The tangents not in the file are computed by their
+// tangent types; slow and fast are evaluated as spline.
+func (c *AnimCurve) Evaluate(t float64) float64 {
+	switch len(c.Keys) {
+	case 0:
+		return 0
+	case 1:
+		return c.Keys[0].Value
+	}
+	first, last := c.Keys[0], c.Keys[len(c.Keys)-1]
+	switch {
+	case t < first.Time:
+		return c.evaluateInfinity(t, c.PreInfinity, true)
+	case last.Time < t:
+		return c.evaluateInfinity(t, c.PostInfinity, false)
+	}
+	return c.evaluateRange(t)
+}
+
+// Bake returns the values of c from start to end by by like the frames
+// 1, 2, ..., 120.
+func (c *AnimCurve) Bake(start, end, by float64) []float64 {
+	if by <= 0 || end < start {
+		return nil
+	}
+	var values []float64
+	for i := 0; ; i++ {
+		t := start + float64(i)*by
+		if end+by*1e-6 < t {
+			break
+		}
+		values = append(values, c.Evaluate(t))
+	}
+	return values
+}
+
+func (c *AnimCurve) evaluateInfinity(t float64, inf Infinity, pre bool) float64 {
+	n := len(c.Keys)
+	first, last := c.Keys[0], c.Keys[n-1]
+	span := last.Time - first.Time
+	switch {
+	case span <= 0:
+	case inf == InfinityLinear:
+		if pre {
+			return first.Value + c.getSlope(0, true)*(t-first.Time)
+		}
+		return last.Value + c.getSlope(n-1, false)*(t-last.Time)
+	case inf == InfinityCycle || inf == InfinityCycleRelative || inf == InfinityOscillate:
+		cycles := math.Floor((t - first.Time) / span)
+		local := t - cycles*span
+		if inf == InfinityOscillate && math.Mod(cycles, 2) != 0 {
+			local = first.Time + last.Time - local
+		}
+		v := c.evaluateRange(local)
+		if inf == InfinityCycleRelative {
+			v += cycles * (last.Value - first.Value)
+		}
+		return v
+	}
+	if pre {
+		return first.Value
+	}
+	return last.Value
+}
+
+// evaluateRange returns the value at t between the first key and the last
+// key.
+func (c *AnimCurve) evaluateRange(t float64) float64 {
+	n := len(c.Keys)
+	i := sort.Search(n, func(i int) bool { return t < c.Keys[i].Time }) - 1
+	switch {
+	case i < 0:
+		return c.Keys[0].Value
+	case n-1 <= i:
+		return c.Keys[n-1].Value
+	}
+	k0, k1 := c.Keys[i], c.Keys[i+1]
+	switch {
+	case t == k0.Time || k0.OutTangentType == TangentStep:
+		return k0.Value
+	case k0.OutTangentType == TangentStepNext:
+		return k1.Value
+	}
+	dt := k1.Time - k0.Time
+	if c.Weighted {
+		ox, oy := c.getTangent(i, false)
+		ix, iy := c.getTangent(i+1, true)
+		x1 := math.Min(math.Max(k0.Time+ox/3, k0.Time), k1.Time)
+		x2 := math.Min(math.Max(k1.Time-ix/3, k0.Time), k1.Time)
+		s := solveBezier(k0.Time, x1, x2, k1.Time, t)
+		return bezier(k0.Value, k0.Value+oy/3, k1.Value-iy/3, k1.Value, s)
+	}
+	m0, m1 := c.getSlope(i, false), c.getSlope(i+1, true)
+	h := (t - k0.Time) / dt
+	h2, h3 := h*h, h*h*h
+	return (2*h3-3*h2+1)*k0.Value + (h3-2*h2+h)*dt*m0 +
+		(-2*h3+3*h2)*k1.Value + (h3-h2)*dt*m1
+}
+
+// getTangent returns the in or out tangent of the key i in frames. The
+// tangent is 3 times of the offset of the Bezier control point.
+func (c *AnimCurve) getTangent(i int, in bool) (float64, float64) {
+	k := c.Keys[i]
+	typ, has, x, y := k.OutTangentType, k.HasOutTangent, k.OutX, k.OutY
+	if in {
+		typ, has, x, y = k.InTangentType, k.HasInTangent, k.InX, k.InY
+	}
+	if has && typ != TangentStep && typ != TangentStepNext {
+		if 0 < c.FPS {
+			x *= c.FPS
+		}
+		if 0 < c.YScale {
+			y *= c.YScale
+		}
+		return x, y
+	}
+	// The computed tangent has the default weight, a third of the segment.
+	var dt float64
+	switch {
+	case len(c.Keys) == 1:
+		dt = 1
+	case (in && 0 < i) || i == len(c.Keys)-1:
+		dt = k.Time - c.Keys[i-1].Time
+	default:
+		dt = c.Keys[i+1].Time - k.Time
+	}
+	return dt, c.computeSlope(i, typ, in) * dt
+}
+
+// getSlope returns the slope of the in or out tangent of the key i. The
+// vertical tangent is evaluated as flat.
+func (c *AnimCurve) getSlope(i int, in bool) float64 {
+	x, y := c.getTangent(i, in)
+	if x == 0 {
+		return 0
+	}
+	return y / x
+}
+
+// computeSlope returns the slope of the tangent type typ of the key i.
+func (c *AnimCurve) computeSlope(i int, typ TangentType, in bool) float64 {
+	n := len(c.Keys)
+	if n < 2 {
+		return 0
+	}
+	switch typ {
+	case TangentFlat, TangentStep, TangentStepNext:
+		return 0
+	case TangentLinear:
+		if (in && 0 < i) || i == n-1 {
+			return c.getSegmentSlope(i-1, i)
+		}
+		return c.getSegmentSlope(i, i+1)
+	case TangentClamped:
+		// Flat next to the key of the same value, not to overshoot it.
+		v := c.Keys[i].Value
+		if (0 < i && c.Keys[i-1].Value == v) || (i < n-1 && c.Keys[i+1].Value == v) {
+			return 0
+		}
+	case TangentPlateau, TangentAuto:
+		// Flat at the ends and the extremes, and clamped not to overshoot
+		// the neighbors.
+		if i == 0 || i == n-1 {
+			return 0
+		}
+		prev, k, next := c.Keys[i-1], c.Keys[i], c.Keys[i+1]
+		if (k.Value-prev.Value)*(next.Value-k.Value) <= 0 {
+			return 0
+		}
+		m := c.getSplineSlope(i)
+		limit := math.Min(
+			3*math.Abs(k.Value-prev.Value)/(k.Time-prev.Time),
+			3*math.Abs(next.Value-k.Value)/(next.Time-k.Time))
+		return math.Copysign(math.Min(math.Abs(m), limit), m)
+	}
+	return c.getSplineSlope(i)
+}
+
+// getSplineSlope returns the slope of the neighbors of the key i.
+func (c *AnimCurve) getSplineSlope(i int) float64 {
+	n := len(c.Keys)
+	switch {
+	case i == 0:
+		return c.getSegmentSlope(0, 1)
+	case i == n-1:
+		return c.getSegmentSlope(n-2, n-1)
+	}
+	return c.getSegmentSlope(i-1, i+1)
+}
+
+func (c *AnimCurve) getSegmentSlope(i, j int) float64 {
+	dt := c.Keys[j].Time - c.Keys[i].Time
+	if dt == 0 {
+		return 0
+	}
+	return (c.Keys[j].Value - c.Keys[i].Value) / dt
+}
+
+func bezier(p0, p1, p2, p3, s float64) float64 {
+	r := 1 - s
+	return r*r*r*p0 + 3*r*r*s*p1 + 3*r*s*s*p2 + s*s*s*p3
+}
+
+// solveBezier returns s of the Bezier curve x0 x1 x2 x3 at x.
+func solveBezier(x0, x1, x2, x3, x float64) float64 {
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 64; i++ {
+		s := (lo + hi) / 2
+		if bezier(x0, x1, x2, x3, s) < x {
+			lo = s
+		} else {
+			hi = s
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// splitAnimCurveAttr returns "ktv" and 2 of ".ktv[2:4]".
+func splitAnimCurveAttr(attr string) (string, int, error) {
+	attr = strings.TrimPrefix(attr, ".")
+	open := strings.Index(attr, "[")
+	if open == -1 {
+		return attr, 0, nil
+	}
+	index := strings.TrimSuffix(attr[open+1:], "]")
+	if colon := strings.Index(index, ":"); colon != -1 {
+		index = index[:colon]
+	}
+	start, err := strconv.Atoi(index)
+	if err != nil || start < 0 {
+		return "", 0, errors.New(fmt.Sprintf("%s has an invalid index", attr))
+	}
+	return attr[:open], start, nil
+}
+
+// getAttrFloats returns the numbers of a. The compounds like double3 are
+// flattened.
+func getAttrFloats(a *Attr) []float64 {
+	var values []float64
+	for _, v := range a.GetAttrValue() {
+		switch v := v.(type) {
+		case *AttrFloat:
+			values = append(values, v.Float())
+		case *AttrInt:
+			values = append(values, float64(v.Int()))
+		case *AttrFloat2:
+			values = append(values, v[:]...)
+		case *AttrFloat3:
+			values = append(values, v[:]...)
+		case *AttrDouble2:
+			values = append(values, v[:]...)
+		case *AttrDouble3:
+			values = append(values, v[:]...)
+		case *AttrBool:
+			if v.Bool() {
+				values = append(values, 1)
+			} else {
+				values = append(values, 0)
+			}
+		}
+	}
+	return values
+}
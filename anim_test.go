@@ -0,0 +1,358 @@
+package mayaascii
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const animTestMa = `currentUnit -l centimeter -a degree -t ntsc;
+createNode transform -n "pCube1";
+createNode animCurveTL -n "pCube1_translateX";
+	setAttr ".tan" 18;
+	setAttr ".wgt" no;
+	setAttr -s 4 ".ktv[0:3]"  1 0 11 10 21 0 31 5.5;
+	setAttr -s 4 ".kit[2:3]"  1 2;
+	setAttr -s 4 ".kot[1:3]"  5 1 17;
+	setAttr ".kix[2]"  0.5;
+	setAttr ".kiy[2]"  15;
+	setAttr ".kl[2]" no;
+	setAttr ".kb[1]" yes;
+	setAttr ".pre" 1;
+	setAttr ".pst" 4;
+createNode animCurveUA -n "pCube1_rotateX";
+	setAttr -s 2 ".ktv[0:1]"  0 0 1 90;
+connectAttr "pCube1_translateX.o" "pCube1.tx";
+`
+
+func TestNode_AsAnimCurve(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(animTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := o.GetNode("pCube1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.AsAnimCurve(); err == nil {
+		t.Errorf("pCube1 AsAnimCurve() got nil error")
+	}
+	n, err = o.GetNode("pCube1_translateX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := n.AsAnimCurve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"IsTimeInput", c.IsTimeInput(), true}, t)
+	floatTester(floatTestData{"FPS", c.FPS, 30}, t)
+	stringTester(stringTestData{"TangentType", c.TangentType.String(), "auto"}, t)
+	boolTester(boolTestData{"Weighted", c.Weighted, false}, t)
+	stringTester(stringTestData{"PreInfinity", c.PreInfinity.String(), "linear"}, t)
+	stringTester(stringTestData{"PostInfinity", c.PostInfinity.String(), "cycleRelative"}, t)
+	intTester(intTestData{"Keys", len(c.Keys), 4}, t)
+
+	var types []string
+	for _, k := range c.Keys {
+		types = append(types, fmt.Sprintf("%v-%v %v %v", k.Time, k.Value, k.InTangentType, k.OutTangentType))
+	}
+	stringTester(stringTestData{"Keys", strings.Join(types, ", "),
+		"1-0 auto auto, 11-10 auto step, 21-0 fixed fixed, 31-5.5 linear stepnext"}, t)
+	k := c.Keys[2]
+	boolTester(boolTestData{"HasInTangent", k.HasInTangent, true}, t)
+	boolTester(boolTestData{"HasOutTangent", k.HasOutTangent, false}, t)
+	floatTester(floatTestData{"InX", k.InX, 0.5}, t)
+	floatTester(floatTestData{"InY", k.InY, 15}, t)
+	boolTester(boolTestData{"TanLocked", k.TanLocked, false}, t)
+	boolTester(boolTestData{"TanLocked[1]", c.Keys[1].TanLocked, true}, t)
+	boolTester(boolTestData{"Breakdown", c.Keys[1].Breakdown, true}, t)
+
+	n, err = o.GetNode("pCube1_rotateX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = n.AsAnimCurve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"UA IsTimeInput", c.IsTimeInput(), false}, t)
+	floatTester(floatTestData{"UA FPS", c.FPS, 0}, t)
+	floatTester(floatTestData{"UA Evaluate", c.Evaluate(0.5), 45}, t)
+}
+
+type animCurveTestData struct {
+	title string
+	curve string
+	times []float64
+	wonts []float64
+}
+
+func TestAnimCurve_Evaluate(t *testing.T) {
+	for _, d := range []animCurveTestData{
+		{
+			title: "empty",
+			curve: ``,
+			times: []float64{0},
+			wonts: []float64{0},
+		},
+		{
+			title: "single key",
+			curve: `setAttr ".ktv[0]"  5 3;
+	setAttr ".pst" 1;`,
+			times: []float64{0, 5, 10},
+			wonts: []float64{3, 3, 3},
+		},
+		{
+			title: "linear",
+			curve: `setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  1 0 11 10;
+	setAttr ".pst" 1;`,
+			times: []float64{-5, 1, 6, 11, 21},
+			wonts: []float64{0, 0, 5, 10, 20},
+		},
+		{
+			title: "step",
+			curve: `setAttr -s 2 ".ktv[0:1]"  1 0 5 10;
+	setAttr ".kot[0]"  5;`,
+			times: []float64{1, 4.9, 5},
+			wonts: []float64{0, 0, 10},
+		},
+		{
+			title: "step next",
+			curve: `setAttr -s 2 ".ktv[0:1]"  1 0 5 10;
+	setAttr ".kot[0]"  17;`,
+			times: []float64{1, 2, 5},
+			wonts: []float64{0, 10, 10},
+		},
+		{
+			title: "flat",
+			curve: `setAttr ".tan" 3;
+	setAttr -s 2 ".ktv[0:1]"  0 0 10 10;`,
+			times: []float64{2.5, 5, 7.5},
+			wonts: []float64{1.5625, 5, 8.4375},
+		},
+		{
+			title: "spline",
+			curve: `setAttr ".tan" 9;
+	setAttr -s 3 ".ktv[0:2]"  0 0 10 10 20 0;`,
+			times: []float64{5, 10, 15},
+			wonts: []float64{6.25, 10, 6.25},
+		},
+		{
+			title: "clamped",
+			curve: `setAttr ".tan" 10;
+	setAttr -s 3 ".ktv[0:2]"  0 0 10 10 20 10;`,
+			times: []float64{5, 15},
+			wonts: []float64{6.25, 10},
+		},
+		{
+			title: "auto flat at the ends and the extremes",
+			curve: `setAttr -s 3 ".ktv[0:2]"  0 0 10 10 20 0;`,
+			times: []float64{5, 15},
+			wonts: []float64{5, 5},
+		},
+		{
+			title: "plateau does not overshoot",
+			curve: `setAttr ".tan" 16;
+	setAttr -s 3 ".ktv[0:2]"  0 0 10 9 20 10;`,
+			times: []float64{15},
+			wonts: []float64{9.875},
+		},
+		{
+			title: "fixed",
+			curve: `setAttr -s 2 ".ktv[0:1]"  0 0 10 10;
+	setAttr -s 2 ".kit[0:1]"  1 1;
+	setAttr -s 2 ".kot[0:1]"  1 1;
+	setAttr -s 2 ".kix[0:1]"  1 1;
+	setAttr -s 2 ".kiy[0:1]"  24 24;
+	setAttr -s 2 ".kox[0:1]"  1 1;
+	setAttr -s 2 ".koy[0:1]"  24 24;
+	setAttr ".pre" 1;`,
+			times: []float64{-2, 5},
+			wonts: []float64{-2, 5},
+		},
+		{
+			title: "weighted",
+			curve: `setAttr ".wgt" yes;
+	setAttr -s 2 ".ktv[0:1]"  0 0 12 12;
+	setAttr -s 2 ".kix[0:1]"  1 1;
+	setAttr -s 2 ".kiy[0:1]"  0 0;
+	setAttr -s 2 ".kox[0:1]"  1 1;
+	setAttr -s 2 ".koy[0:1]"  0 0;`,
+			times: []float64{4.125, 6, 7.875},
+			wonts: []float64{1.875, 6, 10.125},
+		},
+		{
+			title: "weighted linear",
+			curve: `setAttr ".tan" 2;
+	setAttr ".wgt" yes;
+	setAttr -s 2 ".ktv[0:1]"  0 0 12 12;`,
+			times: []float64{3, 6},
+			wonts: []float64{3, 6},
+		},
+		{
+			title: "cycle",
+			curve: `setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  0 0 10 10;
+	setAttr ".pre" 3;
+	setAttr ".pst" 3;`,
+			times: []float64{-8, 12, 20, 25},
+			wonts: []float64{2, 2, 0, 5},
+		},
+		{
+			title: "cycle relative",
+			curve: `setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  0 0 10 10;
+	setAttr ".pre" 4;
+	setAttr ".pst" 4;`,
+			times: []float64{-8, 12, 20, 25},
+			wonts: []float64{-8, 12, 20, 25},
+		},
+		{
+			title: "oscillate",
+			curve: `setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  0 0 10 10;
+	setAttr ".pre" 5;
+	setAttr ".pst" 5;`,
+			times: []float64{-2, 12, 20, 25},
+			wonts: []float64{2, 8, 0, 5},
+		},
+	} {
+		o, err := Unmarshal(strings.NewReader(
+			"currentUnit -t film;\ncreateNode animCurveTL -n \"curve\";\n\t" + d.curve + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := o.GetNode("curve")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := n.AsAnimCurve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, time := range d.times {
+			floatTester(floatTestData{fmt.Sprintf("%s Evaluate(%v)", d.title, time),
+				c.Evaluate(time), d.wonts[i]}, t)
+		}
+	}
+}
+
+func TestAnimCurve_Bake(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode animCurveTU -n "curve";
+	setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  1 0 5 8;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := o.GetNode("curve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := n.AsAnimCurve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Bake", fmt.Sprint(c.Bake(1, 5, 1)), "[0 2 4 6 8]"}, t)
+	stringTester(stringTestData{"Bake half", fmt.Sprint(c.Bake(1, 2, 0.5)), "[0 1 2]"}, t)
+	intTester(intTestData{"Bake by 0", len(c.Bake(1, 5, 0)), 0}, t)
+}
+
+func TestObject_GetCurrentUnit(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(animTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := o.GetCurrentUnit()
+	stringTester(stringTestData{"Linear", u.Linear, "centimeter"}, t)
+	stringTester(stringTestData{"Angle", u.Angle, "degree"}, t)
+	stringTester(stringTestData{"Time", u.Time, "ntsc"}, t)
+	for _, d := range []struct {
+		time string
+		fps  float64
+	}{{"film", 24}, {"ntscf", 60}, {"23.976fps", 23.976}, {"sec", 1}} {
+		fps, err := CurrentUnit{Time: d.time}.GetFPS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		floatTester(floatTestData{d.time, fps, d.fps}, t)
+	}
+	if _, err := (CurrentUnit{Time: "bogus"}).GetFPS(); err == nil {
+		t.Errorf("GetFPS() of bogus got nil error")
+	}
+}
+
+func TestAnimCurve_Evaluate_tangentUnit(t *testing.T) {
+	for _, d := range []struct {
+		title  string
+		scene  string
+		yScale float64
+		wont   float64
+	}{
+		{
+			// The fixed tangents of the linear keys that Maya saved, the
+			// unit vectors of seconds and radians.
+			title: "animCurveTA",
+			scene: `currentUnit -l centimeter -a degree -t film;
+createNode animCurveTA -n "curve";
+	setAttr ".tan" 1;
+	setAttr ".wgt" no;
+	setAttr -s 2 ".ktv[0:1]"  0 0 24 90;
+	setAttr -s 2 ".kix[0:1]"  0.53702926635742188 0.53702926635742188;
+	setAttr -s 2 ".kiy[0:1]"  0.84356361627578735 0.84356361627578735;
+	setAttr -s 2 ".kox[0:1]"  0.53702926635742188 0.53702926635742188;
+	setAttr -s 2 ".koy[0:1]"  0.84356361627578735 0.84356361627578735;
+`,
+			yScale: 180 / math.Pi,
+			wont:   22.5,
+		},
+		{
+			title: "animCurveTL meter",
+			scene: `currentUnit -l meter -a degree -t film;
+createNode animCurveTL -n "curve";
+	setAttr ".tan" 1;
+	setAttr ".wgt" no;
+	setAttr -s 2 ".ktv[0:1]"  0 0 24 1;
+	setAttr -s 2 ".kix[0:1]"  0.0099994996562600136 0.0099994996562600136;
+	setAttr -s 2 ".kiy[0:1]"  0.99994999170303345 0.99994999170303345;
+	setAttr -s 2 ".kox[0:1]"  0.0099994996562600136 0.0099994996562600136;
+	setAttr -s 2 ".koy[0:1]"  0.99994999170303345 0.99994999170303345;
+`,
+			yScale: 0.01,
+			wont:   0.25,
+		},
+		{
+			title: "animCurveTA radian",
+			scene: `currentUnit -l centimeter -a radian -t film;
+createNode animCurveTA -n "curve";
+	setAttr ".tan" 1;
+	setAttr ".wgt" no;
+	setAttr -s 2 ".ktv[0:1]"  0 0 24 1.5707963267948966;
+	setAttr -s 2 ".kix[0:1]"  0.53702926635742188 0.53702926635742188;
+	setAttr -s 2 ".kiy[0:1]"  0.84356361627578735 0.84356361627578735;
+	setAttr -s 2 ".kox[0:1]"  0.53702926635742188 0.53702926635742188;
+	setAttr -s 2 ".koy[0:1]"  0.84356361627578735 0.84356361627578735;
+`,
+			yScale: 1,
+			wont:   math.Pi / 8,
+		},
+	} {
+		o, err := Unmarshal(strings.NewReader(d.scene))
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := o.GetNode("curve")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := n.AsAnimCurve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		floatTester(floatTestData{d.title + " YScale", c.YScale, d.yScale}, t)
+		floatTester(floatTestData{d.title + " Evaluate(6)", c.Evaluate(6), d.wont}, t)
+	}
+}
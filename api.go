@@ -1,6 +1,7 @@
 package mayaascii
 
 import (
+	"context"
 	"io"
 	"strings"
 )
@@ -51,6 +52,72 @@ func Unmarshal(reader io.Reader) (*Object, error) {
 	return mo, nil
 }
 
+// UnmarshalLazy is Unmarshal that decodes the values of setAttr on first
+// access. See Object.UnmarshalLazy.
+func UnmarshalLazy(reader io.Reader) (*Object, error) {
+	mo := &Object{
+		Files:         []*File{},
+		FileInfos:     []*FileInfo{},
+		Requires:      []*Require{},
+		Nodes:         map[string]*Node{},
+		LineComments:  []*LineComment{},
+		BlockComments: []*BlockComment{},
+
+		cmds:        []*Cmd{},
+		connections: NewConnections(),
+	}
+	err := mo.UnmarshalLazy(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return mo, nil
+}
+
+// UnmarshalWithOptions is Unmarshal with the limits for untrusted input.
+// See Object.UnmarshalWithOptions.
+func UnmarshalWithOptions(reader io.Reader, opts UnmarshalOptions) (*Object, error) {
+	mo := &Object{
+		Files:         []*File{},
+		FileInfos:     []*FileInfo{},
+		Requires:      []*Require{},
+		Nodes:         map[string]*Node{},
+		LineComments:  []*LineComment{},
+		BlockComments: []*BlockComment{},
+
+		cmds:        []*Cmd{},
+		connections: NewConnections(),
+	}
+	err := mo.UnmarshalWithOptions(reader, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return mo, nil
+}
+
+// UnmarshalContext is UnmarshalWithOptions that stops when ctx is done and
+// reports the progress to opts.Progress. See Object.UnmarshalContext.
+func UnmarshalContext(ctx context.Context, reader io.Reader, opts UnmarshalOptions) (*Object, error) {
+	mo := &Object{
+		Files:         []*File{},
+		FileInfos:     []*FileInfo{},
+		Requires:      []*Require{},
+		Nodes:         map[string]*Node{},
+		LineComments:  []*LineComment{},
+		BlockComments: []*BlockComment{},
+
+		cmds:        []*Cmd{},
+		connections: NewConnections(),
+	}
+	err := mo.UnmarshalContext(ctx, reader, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return mo, nil
+}
+
 func UnmarshalFocus(reader io.Reader, focusCommands CommandTypes) (*Object, error) {
 	mo := &Object{
 		Files:         []*File{},
@@ -1,6 +1,7 @@
 package mayaascii
 
 import (
+	"math"
 	"strings"
 	"testing"
 )
@@ -44,6 +45,19 @@ func boolTester(d boolTestData, t *testing.T) {
 	}
 }
 
+type floatTestData struct {
+	title string
+	value float64
+	wont  float64
+}
+
+func floatTester(d floatTestData, t *testing.T) {
+	if math.Abs(d.value-d.wont) > 1e-6 {
+		t.Errorf("got %s was %v, wont %v",
+			d.title, d.value, d.wont)
+	}
+}
+
 func TestApi_File(t *testing.T) {
 	reader := strings.NewReader(`//Maya test scene
 file -rdi 1 -ns "test" -rfn "testRN" -typ "mayaAscii" "c:/test_data/test01.ma";
@@ -0,0 +1,193 @@
+package mayaascii
+
+import "strings"
+
+// attrLongNames is the long names of the short attribute names that Maya
+// writes, by the node type. The names of a node type also include the
+// names of its parent types in nodeTypeParents.
+var attrLongNames = map[string]map[string]string{
+	"node": {
+		"msg": "message", "nds": "nodeState", "cch": "caching",
+		"fzn": "frozen", "ihi": "isHistoricallyInteresting",
+	},
+	"dagNode": {
+		"v": "visibility", "lodv": "lodVisibility",
+		"io": "intermediateObject", "tmp": "template",
+		"ovdt": "overrideDisplayType", "ove": "overrideEnabled",
+		"ovc": "overrideColor", "ovv": "overrideVisibility",
+		"ovs": "overrideShading", "ovt": "overrideTexturing",
+		"ovp": "overridePlayback", "ovrgbf": "overrideRGBColors",
+		"ovrgb": "overrideColorRGB", "uocol": "useObjectColor",
+		"oclr": "objectColorRGB", "iog": "instObjGroups",
+		"wm": "worldMatrix", "wim": "worldInverseMatrix",
+		"pm": "parentMatrix", "pim": "parentInverseMatrix",
+		"rlio": "renderLayerInfo",
+	},
+	"transform": {
+		"t": "translate", "tx": "translateX", "ty": "translateY",
+		"tz": "translateZ", "r": "rotate", "rx": "rotateX",
+		"ry": "rotateY", "rz": "rotateZ", "s": "scale", "sx": "scaleX",
+		"sy": "scaleY", "sz": "scaleZ", "sh": "shear", "shxy": "shearXY",
+		"shxz": "shearXZ", "shyz": "shearYZ", "ro": "rotateOrder",
+		"rp": "rotatePivot", "rpt": "rotatePivotTranslate",
+		"sp": "scalePivot", "spt": "scalePivotTranslate",
+		"ra": "rotateAxis", "it": "inheritsTransform",
+		"dsp": "displayScalePivot", "drp": "displayRotatePivot",
+		"dla": "displayLocalAxis", "dh": "displayHandle",
+		"m": "matrix", "xm": "xformMatrix",
+	},
+	"joint": {
+		"jo": "jointOrient", "jox": "jointOrientX", "joy": "jointOrientY",
+		"joz": "jointOrientZ", "ssc": "segmentScaleCompensate",
+		"is": "inverseScale", "radi": "radius", "pa": "preferredAngle",
+		"dl": "drawLabel", "typ": "type", "otp": "otherType", "sd": "side",
+	},
+	"camera": {
+		"fl": "focalLength", "cap": "cameraAperture",
+		"hfa": "horizontalFilmAperture", "vfa": "verticalFilmAperture",
+		"hfo": "horizontalFilmOffset", "vfo": "verticalFilmOffset",
+		"lsr": "lensSqueezeRatio", "ff": "filmFit", "coi": "centerOfInterest",
+		"ncp": "nearClipPlane", "fcp": "farClipPlane",
+		"o": "orthographic", "ow": "orthographicWidth",
+		"fs": "fStop", "fd": "focusDistance", "dof": "depthOfField",
+		"rnd": "renderable", "imn": "imageName", "den": "depthName",
+		"man": "maskName", "tp": "tumblePivot",
+	},
+	"mesh": {
+		"i": "inMesh", "o": "outMesh", "w": "worldMesh", "pt": "pnts",
+		"uvst": "uvSet", "cuvs": "currentUVSet",
+		"dcc": "displayColorChannel", "dcol": "displayColors",
+	},
+	"nurbsCurve": {"cr": "create", "ws": "worldSpace", "l": "local"},
+	"locator":    {"lp": "localPosition", "los": "localScale"},
+	"time":       {"o": "outTime"},
+	"unitConversion": {
+		"i": "input", "o": "output", "cf": "conversionFactor",
+	},
+	"animCurve": {
+		"i": "input", "o": "output", "tan": "tangentType",
+		"wgt": "weightedTangents", "ktv": "keyTimeValue",
+		"kit": "keyTanInType", "kot": "keyTanOutType",
+		"kix": "keyTanInX", "kiy": "keyTanInY",
+		"kox": "keyTanOutX", "koy": "keyTanOutY",
+		"pre": "preInfinity", "pst": "postInfinity",
+	},
+	"expression": {
+		"ixp": "internalExpression", "in": "input", "out": "output",
+		"obm": "objectMsg", "uno": "unitOption", "tim": "time",
+	},
+	"script": {
+		"b": "before", "a": "after", "st": "scriptType", "stp": "sourceType",
+	},
+}
+
+// nodeTypeParents is the parent types of the node types of attrLongNames.
+// The other node types have the names of "node" only.
+var nodeTypeParents = map[string]string{
+	"dagNode": "node", "transform": "dagNode", "joint": "transform",
+	"ikHandle": "transform", "lodGroup": "transform",
+	"aimConstraint": "transform", "geometryConstraint": "transform",
+	"normalConstraint": "transform", "orientConstraint": "transform",
+	"parentConstraint": "transform", "pointConstraint": "transform",
+	"poleVectorConstraint": "transform", "scaleConstraint": "transform",
+	"tangentConstraint": "transform", "ikEffector": "transform",
+	"camera": "dagNode", "mesh": "dagNode", "nurbsCurve": "dagNode",
+	"nurbsSurface": "dagNode", "locator": "dagNode",
+	"ambientLight": "dagNode", "areaLight": "dagNode",
+	"directionalLight": "dagNode", "pointLight": "dagNode",
+	"spotLight": "dagNode", "volumeLight": "dagNode",
+	"animCurveTA": "animCurve", "animCurveTL": "animCurve",
+	"animCurveTT": "animCurve", "animCurveTU": "animCurve",
+	"animCurveUA": "animCurve", "animCurveUL": "animCurve",
+	"animCurveUT": "animCurve", "animCurveUU": "animCurve",
+	"animCurve": "node", "time": "node", "unitConversion": "node",
+	"expression": "node", "script": "node",
+}
+
+// getLongAttrName returns the attribute name with the long names like
+// ".translate[0].translateX" of ".t[0].tx". The short names of the
+// addAttr of n are also replaced. The short names unknown for the type of
+// n are kept.
+func (n *Node) getLongAttrName(name string) string {
+	return mapAttrLeaves(name, n.getLongAttrLeaf)
+}
+
+// mapAttrLeaves returns the attribute name with the names like "t" of
+// ".t[0].tx" replaced by f, keeping the indices.
+func mapAttrLeaves(name string, f func(leaf string) string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		leaf, index := part, ""
+		if j := strings.Index(part, "["); j != -1 {
+			leaf, index = part[:j], part[j:]
+		}
+		parts[i] = f(leaf) + index
+	}
+	return strings.Join(parts, ".")
+}
+
+// getLongAttrLeaf returns the long name of the short name like "tx" of n.
+func (n *Node) getLongAttrLeaf(leaf string) string {
+	for _, a := range n.Attrs {
+		aa, ok := a.attrCmd.(*AddAttrCmd)
+		if ok && aa.ShortName != nil && *aa.ShortName == leaf && aa.LongName != nil {
+			return *aa.LongName
+		}
+	}
+	return getTypeLongAttrLeaf(n.GetType(), leaf)
+}
+
+// getTypeLongAttrLeaf returns the long name of the short name like "tx"
+// of the node type.
+func getTypeLongAttrLeaf(nodeType, leaf string) string {
+	for nodeType != "" {
+		if long, ok := attrLongNames[nodeType][leaf]; ok {
+			return long
+		}
+		parent, ok := nodeTypeParents[nodeType]
+		if !ok && nodeType != "node" {
+			parent = "node"
+		}
+		nodeType = parent
+	}
+	return leaf
+}
+
+// isSameAttrName reports whether the attribute names of n are the same
+// with the short or the long names like ".t" and ".translate".
+func (n *Node) isSameAttrName(name1, name2 string) bool {
+	return name1 == name2 || n.getLongAttrName(name1) == n.getLongAttrName(name2)
+}
+
+// isSameAttrNameOfAnyType is isSameAttrName of a node whose type is not
+// known, true if the names are the same for one of the node types.
+func isSameAttrNameOfAnyType(name1, name2 string) bool {
+	if name1 == name2 {
+		return true
+	}
+	for nodeType := range attrLongNames {
+		long := func(leaf string) string { return getTypeLongAttrLeaf(nodeType, leaf) }
+		if mapAttrLeaves(name1, long) == mapAttrLeaves(name2, long) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimAttrIndices returns the attribute name without the indices like
+// ".pnts.pz" of ".pnts[3].pz".
+func trimAttrIndices(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, c := range name {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && 0 < depth:
+			depth--
+		case depth == 0:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
@@ -46,34 +46,91 @@ type CmdBuilder struct {
 	cmdLine        []string
 	lineNo         uint
 	isBlockComment bool
+
+	// lexState is the state of the lexer at the end of the last line.
+	lexState lexState
+	// isTerminated reports whether the last token is ";".
+	isTerminated bool
+	hasToken     bool
+	// size is the bytes of Raw and tokens is the tokens of the Lexer.
+	size   int
+	tokens int
 }
 
 func (c *CmdBuilder) Append(line string) {
-	if !c.isBlockComment && TypeBlockComment.HasPrefix(line) {
+	if !c.isBlockComment && c.IsClear() && TypeBlockComment.HasPrefix(line) {
 		c.isBlockComment = true
 	}
+	if !c.IsClear() {
+		// The line break of Raw.
+		c.lex("\n")
+		c.size++
+	}
+	c.lex(line)
 	c.cmdLine = append(c.cmdLine, line)
+	c.size += len(line)
 	c.lineNo++
 }
 
+// lex updates the state of the command with the tokens of s.
+func (c *CmdBuilder) lex(s string) {
+	l := NewLexer(s)
+	l.state = c.lexState
+	for {
+		t := l.Next()
+		switch t.Type {
+		case LexEOF:
+			c.lexState = l.state
+			return
+		}
+		c.tokens++
+		switch t.Type {
+		case LexLineComment:
+		case LexIllegal:
+			if l.state == lexStateBlockComment {
+				break
+			}
+			// The string continues to the next line.
+			c.isTerminated = false
+			c.hasToken = true
+		case LexBlockComment:
+			if !c.hasToken {
+				// The block comment command.
+				c.isTerminated = true
+			}
+		case LexSemicolon:
+			c.isTerminated = true
+			c.hasToken = true
+		default:
+			c.isTerminated = false
+			c.hasToken = true
+		}
+	}
+}
+
+// IsCmdEOF reports whether the command ends at the last line, that is
+// the last token is ";" outside of strings and comments.
 func (c *CmdBuilder) IsCmdEOF() bool {
 	if len(c.cmdLine) == 0 {
 		return false
 	}
-	lastLine := c.cmdLine[len(c.cmdLine)-1]
-	if len(lastLine) == 0 {
-		return false
-	}
-	if c.isBlockComment {
-		return strings.HasSuffix(
-			strings.TrimRight(lastLine, " \t\r\n"), "*/")
-	} else {
-		return lastLine[len(lastLine)-1] == byte(';')
-	}
+	return c.isTerminated && c.lexState == lexStateNone
+}
+
+// isInString reports whether the last line ends in a string, so the next
+// line is a part of the string even if it starts with "//".
+func (c *CmdBuilder) isInString() bool {
+	return c.lexState == lexStateString || c.lexState == lexStateStringEscape
 }
 
 func (c *CmdBuilder) Clear() {
 	c.cmdLine = []string{}
+	c.isBlockComment = false
+	c.lexState = lexStateNone
+	c.isTerminated = false
+	c.hasToken = false
+	c.size = 0
+	c.tokens = 0
 }
 
 func (c *CmdBuilder) IsClear() bool {
@@ -81,22 +138,10 @@ func (c *CmdBuilder) IsClear() bool {
 }
 
 const (
-	whiteSpace        = ' '
-	tabSpace          = '\t'
-	enter             = '\n'
-	slash             = '/'
-	asterisk          = '*'
-	backSlash         = '\\'
-	dQuatation        = '"'
-	sQuatation        = '\''
-	hyphen            = '-'
-	plus              = '+'
-	openRoundBracket  = '('
-	closeRoundBracket = ')'
-	kanma             = ','
-	semiCoron         = ';'
-	openBrancket      = '{'
-	closeBrancket     = '}'
+	whiteSpace = ' '
+	tabSpace   = '\t'
+	enter      = '\n'
+	semiCoron  = ';'
 )
 
 type Cmd struct {
@@ -106,149 +151,212 @@ type Cmd struct {
 	LineNo uint
 }
 
+// Parse returns the command of the lines with the tokens of the Lexer.
+// A comment command has the "//" or "/*" token and the text. The strings
+// in {} are the tokens without the quotes between the "{" and "}" tokens,
+// and the strings in () joined by "+" are a single string. The comments in
+// a command and the tokens after ";" are dropped.
 func (c *CmdBuilder) Parse() *Cmd {
 	cmd := Cmd{
 		Raw:    strings.Join(c.cmdLine, "\n"),
 		LineNo: c.lineNo,
 		Type:   TypeNone,
 	}
-	// fmt.Println("Raw", cmd.Raw)
 	c.Clear()
-	var buf []rune
-	var subBuf []rune
-	var subToken []string
-	for _, c := range cmd.Raw {
-		// comment
-		if (0 == len(buf) && c == slash) ||
-			(1 == len(buf) && buf[0] == slash && (c == slash || c == asterisk)) {
-			buf = append(buf, c)
-			if 2 == len(buf) {
-				cmd.Token = append(cmd.Token, string(buf))
-				buf = buf[:0]
-				if c == slash {
-					cmd.Type = TypeLineComment
-				} else if c == asterisk {
-					cmd.Type = TypeBlockComment
-				}
-			}
-			continue
-		}
-		if cmd.Type == TypeBlockComment {
-			if 2 <= len(buf) && buf[len(buf)-1] == asterisk && c == slash {
-				cmd.Token = append(cmd.Token, string(buf[:len(buf)-1]))
-				break
-			}
-			buf = append(buf, c)
-			continue
-		}
-		if cmd.Type == TypeLineComment {
-			buf = append(buf, c)
-			continue
-		}
-		if 0 < len(buf) && buf[len(buf)-1] == backSlash {
-			buf = append(buf, c)
-			subBuf = append(subBuf, c)
-			continue
-		}
-		if (c == openBrancket && 0 == len(buf)) ||
-			(0 < len(buf) && buf[0] == openBrancket) {
-			// {"attribute","alias"
-			// 		"attribute","alias"}
-			buf = append(buf, c)
-			if len(buf) == 1 {
-				cmd.Token = append(cmd.Token, string(c))
-				// buf = [:0] はしない
+	l := NewLexer(cmd.Raw)
+	// strs is the strings in () without the quotes.
+	var strs []string
+	inBrace, inParen := false, false
+	for t := l.Next(); t.Type != LexEOF && t.Type != LexSemicolon && t.Type != LexIllegal; t = l.Next() {
+		switch t.Type {
+		case LexLineComment, LexBlockComment:
+			if len(cmd.Token) != 0 {
 				continue
 			}
-			if c == dQuatation ||
-				(len(subBuf) > 0 && subBuf[0] == dQuatation) {
-				subBuf = append(subBuf, c)
-				if len(subBuf) > 1 && c == dQuatation {
-					cmd.Token = append(cmd.Token, strings.Trim(string(subBuf), "\""))
-					subBuf = subBuf[:0]
-				}
+			if t.Type == LexLineComment {
+				cmd.Token = []string{t.Value[:2], t.Value[2:]}
+			} else {
+				cmd.Token = []string{t.Value[:2], t.Value[2 : len(t.Value)-2]}
 			}
-			if c == whiteSpace ||
-				c == tabSpace ||
-				c == kanma ||
-				c == enter {
-				continue
+			cmd.Type = Type(cmd.Token[0])
+			return &cmd
+		case LexOpenBrace:
+			inBrace = true
+			cmd.Token = append(cmd.Token, t.Value)
+		case LexCloseBrace:
+			inBrace = false
+			cmd.Token = append(cmd.Token, t.Value)
+		case LexOpenParen:
+			inParen = true
+		case LexCloseParen:
+			inParen = false
+			cmd.Token = append(cmd.Token, `"`+strings.Join(strs, "")+`"`)
+			strs = strs[:0]
+		case LexString:
+			switch {
+			case inParen:
+				strs = append(strs, t.Value[1:len(t.Value)-1])
+			case inBrace:
+				cmd.Token = append(cmd.Token, t.Value[1:len(t.Value)-1])
+			default:
+				cmd.Token = append(cmd.Token, t.Value)
 			}
-			if c == closeBrancket {
-				cmd.Token = append(cmd.Token, string(c))
-				buf = buf[:0]
+		case LexComma:
+		default:
+			if inBrace || inParen {
+				// "+" and the other words between the strings.
 				continue
 			}
+			cmd.Token = append(cmd.Token, t.Value)
 		}
-		if (c == openRoundBracket && 0 == len(buf)) ||
-			(0 < len(buf) && buf[0] == openRoundBracket) {
-			// ( "long text" +
-			//   	"long text")
-			buf = append(buf, c)
-			if len(buf) == 1 {
-				continue
-			}
-			if c == dQuatation ||
-				(len(subBuf) > 0 && subBuf[0] == dQuatation) {
-				subBuf = append(subBuf, c)
-				if len(subBuf) > 1 && c == dQuatation {
-					if len(subToken) == 0 {
-						subToken = append(subToken, "\"")
-					}
-					subToken = append(subToken, string(subBuf)[1:len(string(subBuf))-1])
-					subBuf = subBuf[:0]
-				}
-				continue
-			}
-			if c == whiteSpace ||
-				c == tabSpace ||
-				c == enter ||
-				c == plus {
-				continue
-			}
-			if c == closeRoundBracket {
-				subToken = append(subToken, "\"")
-				cmd.Token = append(cmd.Token, strings.Join(subToken, ""))
-				buf = buf[:0]
-				subToken = subToken[:0]
-				continue
-			}
+	}
+	if 0 < len(cmd.Token) {
+		cmd.Type = Type(cmd.Token[0])
+	}
+	return &cmd
+}
+
+// NewCmd tokenizes a single command written in Maya ASCII syntax.
+// It is used to build commands that were not read from a file.
+func NewCmd(raw string) *Cmd {
+	cb := &CmdBuilder{}
+	cb.Append(raw)
+	c := cb.Parse()
+	c.LineNo = 0
+	return c
+}
+
+// replaceToken replaces every token equal to old with new, in Token and
+// in Raw. Raw keeps its original formatting.
+func (c *Cmd) replaceToken(old, new string) {
+	if c == nil || old == new {
+		return
+	}
+	replaced := false
+	for i, t := range c.Token {
+		if t == old {
+			c.Token[i] = new
+			replaced = true
 		}
-		if (c == dQuatation && 0 == len(buf)) ||
-			(0 < len(buf) && buf[0] == dQuatation) {
-			// "text\"s"
-			buf = append(buf, c)
-			if len(buf) > 1 && c == dQuatation {
-				cmd.Token = append(cmd.Token, string(buf))
-				buf = buf[:0]
-				continue
-			}
+	}
+	if !replaced {
+		return
+	}
+	var buf strings.Builder
+	raw := c.Raw
+	for {
+		i := indexToken(raw, old)
+		if i == -1 {
+			break
+		}
+		buf.WriteString(raw[:i])
+		buf.WriteString(new)
+		raw = raw[i+len(old):]
+	}
+	buf.WriteString(raw)
+	c.Raw = buf.String()
+}
+
+// setFlag sets the value of the flag like "-p", or adds the flag before
+// the first flag, or after the command name, when it is not found.
+func (c *Cmd) setFlag(flag, value string) {
+	if len(c.Token) == 0 {
+		return
+	}
+	raw := c.Raw
+	found := false
+	for i := 1; i < len(c.Token)-1; i++ {
+		if c.Token[i] != flag {
 			continue
 		}
-		if c == whiteSpace || c == tabSpace || c == enter {
-			if len(buf) != 0 {
-				cmd.Token = append(cmd.Token, string(buf))
-				buf = buf[:0]
+		flagIndex := indexToken(raw, flag)
+		valueIndex := flagIndex + len(flag) + indexToken(raw[flagIndex+len(flag):], c.Token[i+1])
+		raw = raw[:valueIndex] + value + raw[valueIndex+len(c.Token[i+1]):]
+		found = true
+		break
+	}
+	if !found {
+		i := indexToken(raw, c.Token[0]) + len(c.Token[0])
+		offset := i
+		for _, t := range c.Token[1:] {
+			ti := offset + indexToken(raw[offset:], t)
+			if isFlag(t) {
+				i = ti - 1
+				break
 			}
+			offset = ti + len(t)
+		}
+		raw = raw[:i] + " " + flag + " " + value + raw[i:]
+	}
+	c.retokenize(raw)
+}
+
+// removeFlag removes the flag like "-p" and its value.
+func (c *Cmd) removeFlag(flag string) {
+	for i := 1; i < len(c.Token)-1; i++ {
+		if c.Token[i] != flag {
 			continue
 		}
-		if c == semiCoron {
-			if len(buf) != 0 {
-				cmd.Token = append(cmd.Token, string(buf))
-			}
-			break
+		raw := c.Raw
+		flagIndex := indexToken(raw, flag)
+		valueIndex := flagIndex + len(flag) + indexToken(raw[flagIndex+len(flag):], c.Token[i+1])
+		end := valueIndex + len(c.Token[i+1])
+		for flagIndex > 0 && (raw[flagIndex-1] == whiteSpace || raw[flagIndex-1] == tabSpace) {
+			flagIndex--
 		}
-		buf = append(buf, c)
+		c.retokenize(raw[:flagIndex] + raw[end:])
+		return
 	}
-	if 0 != len(buf) && cmd.Type == TypeLineComment {
-		cmd.Token = append(cmd.Token, string(buf))
+}
+
+// removeSwitch removes the flag without a value like "-ss" from Raw.
+func (c *Cmd) removeSwitch(flag string) {
+	flagIndex := indexToken(c.Raw, flag)
+	if flagIndex == -1 {
+		return
 	}
-	if 0 == len(cmd.Token) {
-		cmd.Type = TypeNone
-	} else {
-		cmd.Type = Type(cmd.Token[0])
+	raw := c.Raw
+	end := flagIndex + len(flag)
+	for flagIndex > 0 && (raw[flagIndex-1] == whiteSpace || raw[flagIndex-1] == tabSpace) {
+		flagIndex--
+	}
+	c.retokenize(raw[:flagIndex] + raw[end:])
+}
+
+// isFlag reports whether the token is a flag like "-p", not a negative
+// number.
+func isFlag(t string) bool {
+	return len(t) > 1 && t[0] == '-' &&
+		(('a' <= t[1] && t[1] <= 'z') || ('A' <= t[1] && t[1] <= 'Z'))
+}
+
+func (c *Cmd) retokenize(raw string) {
+	nc := NewCmd(raw)
+	c.Raw = nc.Raw
+	c.Token = nc.Token
+}
+
+func isTokenSeparator(c byte) bool {
+	return c == whiteSpace || c == tabSpace || c == enter || c == '\r' || c == semiCoron
+}
+
+// indexToken returns the index of the first old in raw that is not a part of
+// a longer token, or -1.
+func indexToken(raw, old string) int {
+	offset := 0
+	for {
+		i := strings.Index(raw[offset:], old)
+		if i == -1 {
+			return -1
+		}
+		i += offset
+		end := i + len(old)
+		if (i == 0 || isTokenSeparator(raw[i-1])) &&
+			(end == len(raw) || isTokenSeparator(raw[end])) {
+			return i
+		}
+		offset = i + 1
 	}
-	return &cmd
 }
 
 type LineCommentCmd struct {
@@ -271,6 +379,38 @@ type FileCmd struct {
 	Type               string `json:"type" type:"-type"`
 	Reference          bool   `json:"reference" type:"-reference"`
 	DeferReference     bool   `json:"defer_reference" type:"-deferReference"`
+
+	OptionValues           []FileOption        `json:"option_values,omitempty"`
+	RenamingPrefix         *string             `json:"renaming_prefix,omitempty" type:"-renamingPrefix"`
+	SharedNodes            []string            `json:"shared_nodes,omitempty" type:"-sharedNodes"`
+	GroupLocator           bool                `json:"group_locator" type:"-groupLocator"`
+	MergeNamespacesOnClash *bool               `json:"merge_namespaces_on_clash,omitempty" type:"-mergeNamespacesOnClash"`
+	LoadReferenceDepth     string              `json:"load_reference_depth,omitempty" type:"-loadReferenceDepth"`
+	SwapNamespaces         []FileSwapNamespace `json:"swap_namespaces,omitempty" type:"-swapNamespace"`
+	IgnoreVersion          bool                `json:"ignore_version" type:"-ignoreVersion"`
+	PreserveReferences     bool                `json:"preserve_references" type:"-preserveReferences"`
+	ExportSelected         bool                `json:"export_selected" type:"-exportSelected"`
+	// UnknownFlags is the tokens of the flags that are not known, like
+	// the flags of newer Maya, with their values as written.
+	UnknownFlags []string `json:"unknown_flags,omitempty"`
+}
+
+// FileOption is a key and value of the -op flag like "v=0".
+type FileOption struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// FileSwapNamespace is a pair of the -sns flag.
+type FileSwapNamespace struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IsReferenceDepthInfo reports whether the command is the "file -rdi"
+// prelude that describes the reference hierarchy, not a reference to load.
+func (f *FileCmd) IsReferenceDepthInfo() bool {
+	return !f.Reference && f.ReferenceDepthInfo != 0
 }
 
 func (f *FileCmd) String() string {
@@ -288,6 +428,41 @@ func (f *FileCmd) String() string {
 	if f.DeferReference {
 		buf.WriteString(" -dr 1")
 	}
+	if f.RenamingPrefix != nil {
+		buf.WriteString(fmt.Sprintf(" -rpr \"%s\"", *f.RenamingPrefix))
+	}
+	for _, shd := range f.SharedNodes {
+		buf.WriteString(fmt.Sprintf(" -shd \"%s\"", shd))
+	}
+	if f.GroupLocator {
+		buf.WriteString(" -gl")
+	}
+	if f.MergeNamespacesOnClash != nil {
+		if *f.MergeNamespacesOnClash {
+			buf.WriteString(" -mnc 1")
+		} else {
+			buf.WriteString(" -mnc 0")
+		}
+	}
+	if f.LoadReferenceDepth != "" {
+		buf.WriteString(fmt.Sprintf(" -lrd \"%s\"", f.LoadReferenceDepth))
+	}
+	for _, sns := range f.SwapNamespaces {
+		buf.WriteString(fmt.Sprintf(" -sns \"%s\" \"%s\"", sns.From, sns.To))
+	}
+	if f.IgnoreVersion {
+		buf.WriteString(" -iv")
+	}
+	if f.PreserveReferences {
+		buf.WriteString(" -pr")
+	}
+	if f.ExportSelected {
+		buf.WriteString(" -es")
+	}
+	for _, t := range f.UnknownFlags {
+		buf.WriteString(" ")
+		buf.WriteString(t)
+	}
 	buf.WriteString(" -rfn \"")
 	buf.WriteString(f.ReferenceNode)
 	buf.WriteString("\" -op \"")
@@ -361,6 +536,27 @@ type RequiresCmd struct {
 	DataTypes  []string `json:"data_types" tag:"-dataType"`
 }
 
+func (r *RequiresCmd) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("requires ")
+	for _, nt := range r.NodeTypes {
+		buf.WriteString("-nodeType \"")
+		buf.WriteString(nt)
+		buf.WriteString("\" ")
+	}
+	for _, dt := range r.DataTypes {
+		buf.WriteString("-dataType \"")
+		buf.WriteString(dt)
+		buf.WriteString("\" ")
+	}
+	buf.WriteString("\"")
+	buf.WriteString(r.PluginName)
+	buf.WriteString("\" \"")
+	buf.WriteString(r.Version)
+	buf.WriteString("\";")
+	return buf.String()
+}
+
 type ConnectAttrCmd struct {
 	*Cmd
 	SrcNode       string  `json:"src_node"`
@@ -715,24 +911,33 @@ type AddAttrCmd struct {
 	NodeName            *string               `json:"node_name,omitempty"`
 }
 
+// GetName returns the long name without "." like "mass".
 func (a *AddAttrCmd) GetName() string {
-	panic("implement me")
+	if a.LongName != nil {
+		return *a.LongName
+	}
+	if a.ShortName != nil {
+		return *a.ShortName
+	}
+	return ""
 }
 
 func (a *AddAttrCmd) IsChannelBox() bool {
-	panic("implement me")
+	return a.IsKeyable()
 }
 
 func (a *AddAttrCmd) IsKeyable() bool {
-	panic("implement me")
+	return a.Keyable != nil && *a.Keyable
 }
 
 func (a *AddAttrCmd) GetAttrType() SetAttrType {
-	panic("implement me")
+	return SetAttrTypeInvalid
 }
 
+// GetAttrValue returns nil. The value of a dynamic attribute is set by
+// setAttr.
 func (a *AddAttrCmd) GetAttrValue() []AttrValue {
-	panic("implement me")
+	return nil
 }
 
 type AttrValue interface {
@@ -2520,44 +2725,15 @@ type ReferenceEdit struct {
 	Relationships   []*RECmdRelationship   `json:"relationships"`
 	Locks           []*RECmdLock           `json:"locks"`
 	Unlocks         []*RECmdUnlock         `json:"unlocks"`
+
+	// edits is the parsed edits in the order of the file.
+	edits []fmt.Stringer
 }
 
 func (re *ReferenceEdit) String(buf *strings.Builder) {
 	buf.WriteString(fmt.Sprintf("\t\t\"%s\" %d\n", re.ReferenceNode, re.CommandNum))
-	for _, parent := range re.Parents {
-		buf.WriteString(parent.String())
-		buf.WriteRune('\n')
-	}
-	for _, addAttr := range re.AddAttrs {
-		buf.WriteString(addAttr.String())
-		buf.WriteRune('\n')
-	}
-	for _, setAttr := range re.SetAttrs {
-		buf.WriteString(setAttr.String())
-		buf.WriteRune('\n')
-	}
-	for _, disconnectAttr := range re.DisconnectAttrs {
-		buf.WriteString(disconnectAttr.String())
-		buf.WriteRune('\n')
-	}
-	for _, deleteAttr := range re.DeleteAttrs {
-		buf.WriteString(deleteAttr.String())
-		buf.WriteRune('\n')
-	}
-	for _, connectAttr := range re.ConnectAttrs {
-		buf.WriteString(connectAttr.String())
-		buf.WriteRune('\n')
-	}
-	for _, relationship := range re.Relationships {
-		buf.WriteString(relationship.String())
-		buf.WriteRune('\n')
-	}
-	for _, lock := range re.Locks {
-		buf.WriteString(lock.String())
-		buf.WriteRune('\n')
-	}
-	for _, unlock := range re.Unlocks {
-		buf.WriteString(unlock.String())
+	for _, c := range re.getCmds() {
+		buf.WriteString(c.Edit.String())
 		buf.WriteRune('\n')
 	}
 }
@@ -2567,104 +2743,8 @@ func (re *ReferenceEdit) StringWrite(writer io.StringWriter) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	for _, parent := range re.Parents {
-		na, err := writer.WriteString(parent.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, addAttr := range re.AddAttrs {
-		na, err := writer.WriteString(addAttr.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, setAttr := range re.SetAttrs {
-		na, err := writer.WriteString(setAttr.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, disconnectAttr := range re.DisconnectAttrs {
-		na, err := writer.WriteString(disconnectAttr.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, deleteAttr := range re.DeleteAttrs {
-		na, err := writer.WriteString(deleteAttr.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, connectAttr := range re.ConnectAttrs {
-		na, err := writer.WriteString(connectAttr.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, relationship := range re.Relationships {
-		na, err := writer.WriteString(relationship.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, lock := range re.Locks {
-		na, err := writer.WriteString(lock.String())
-		if err != nil {
-			return 0, err
-		}
-		n += na
-		na, err = writer.WriteString("\n")
-		if err != nil {
-			return 0, err
-		}
-		n += na
-	}
-	for _, unlock := range re.Unlocks {
-		na, err := writer.WriteString(unlock.String())
+	for _, c := range re.getCmds() {
+		na, err := writer.WriteString(c.Edit.String())
 		if err != nil {
 			return 0, err
 		}
@@ -2,6 +2,7 @@ package mayaascii
 
 import (
 	"log"
+	"strings"
 	"testing"
 )
 
@@ -212,4 +213,71 @@ func TestCmdBuilder_Parse(t *testing.T) {
 			`"long text long text"`,
 		},
 	)
+	testParse(
+		t,
+		`createNode transform /* comment */ -n "a";`,
+		"createNode",
+		[]string{
+			"createNode",
+			"transform",
+			"-n",
+			`"a"`,
+		},
+	)
+	testParse(
+		t,
+		`/* comment */`,
+		"/*",
+		[]string{
+			"/*",
+			" comment ",
+		},
+	)
+}
+
+func TestCmdBuilder_IsCmdEOF_Lexer(t *testing.T) {
+	for _, d := range []struct {
+		lines []string
+		wont  []bool
+	}{
+		// A string ending in ";" at a line break.
+		{[]string{`file -r -op "v=0;`, `p=17;" "a.ma";`}, []bool{false, true}},
+		{[]string{`setAttr ".b" -type "string" "a \";`, `b";`}, []bool{false, true}},
+		// A trailing comment.
+		{[]string{`createNode transform -n "a"; // comment`}, []bool{true}},
+		{[]string{`createNode transform // comment;`, `-n "a";`}, []bool{false, true}},
+		// CRLF.
+		{[]string{"setAttr \".v\" no;\r"}, []bool{true}},
+		{[]string{"/* comment", "*/"}, []bool{false, true}},
+	} {
+		cb := &CmdBuilder{}
+		for i, line := range d.lines {
+			cb.Append(line)
+			boolTester(boolTestData{line, cb.IsCmdEOF(), d.wont[i]}, t)
+		}
+	}
+}
+
+func TestUnmarshal_Lexer(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader("//Maya ASCII 2019 scene\r\n" +
+		"file -r -ns \"a\" -rfn \"aRN\" -op \"v=0;\r\n" +
+		"p=17;\" -typ \"mayaAscii\" \"a.ma\";\r\n" +
+		"createNode script -n \"sceneConfigurationScriptNode\"; // trailing comment\r\n" +
+		"\tsetAttr \".b\" -type \"string\" \"playbackOptions -min 1 -max 24;\r\n" +
+		"// not a comment\";\r\n" +
+		"\tsetAttr \".st\" 6;\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len(Files)", len(mo.Files), 1}, t)
+	stringTester(stringTestData{"GetOptions()", mo.Files[0].GetOptions(), "v=0;\np=17;"}, t)
+	intTester(intTestData{"len(LineComments)", len(mo.LineComments), 1}, t)
+	n, err := mo.GetNode("sceneConfigurationScriptNode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len(Attrs)", len(n.Attrs), 2}, t)
+	stringTester(stringTestData{".b", n.GetAttr(".b").GetAttrValue()[0].String(),
+		"playbackOptions -min 1 -max 24;\n// not a comment"}, t)
+	intTester(intTestData{".st", int(*n.GetAttr(".st").GetAttrValue()[0].(*AttrInt)), 6}, t)
 }
@@ -0,0 +1,107 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CurrentUnit is the currentUnit of the scene.
+type CurrentUnit struct {
+	Linear string
+	Angle  string
+	Time   string
+}
+
+var timeUnitFPS = map[string]float64{
+	"hour":     1.0 / 3600,
+	"min":      1.0 / 60,
+	"sec":      1,
+	"millisec": 1000,
+	"game":     15,
+	"film":     24,
+	"pal":      25,
+	"ntsc":     30,
+	"show":     48,
+	"palf":     50,
+	"ntscf":    60,
+}
+
+var linearUnitCentimeters = map[string]float64{
+	"millimeter": 0.1,
+	"mm":         0.1,
+	"centimeter": 1,
+	"cm":         1,
+	"meter":      100,
+	"m":          100,
+	"kilometer":  100000,
+	"km":         100000,
+	"inch":       2.54,
+	"in":         2.54,
+	"foot":       30.48,
+	"ft":         30.48,
+	"yard":       91.44,
+	"yd":         91.44,
+	"mile":       160934.4,
+	"mi":         160934.4,
+}
+
+// GetCurrentUnit returns the last currentUnit of the scene. The units not
+// in the scene are Maya's defaults "centimeter", "degree" and "film".
+func (o *Object) GetCurrentUnit() CurrentUnit {
+	u := CurrentUnit{Linear: "centimeter", Angle: "degree", Time: "film"}
+	for _, c := range o.cmds {
+		if c.Type != "currentUnit" {
+			continue
+		}
+		for i := 1; i+1 < len(c.Token); i++ {
+			switch c.Token[i] {
+			case "-l", "-linear":
+				u.Linear = trimQuote(c.Token[i+1])
+			case "-a", "-angle":
+				u.Angle = trimQuote(c.Token[i+1])
+			case "-t", "-time":
+				u.Time = trimQuote(c.Token[i+1])
+			default:
+				continue
+			}
+			i++
+		}
+	}
+	return u
+}
+
+// GetFPS returns the frames per second of Time like 24 of "film" and
+// 23.976 of "23.976fps".
+func (u CurrentUnit) GetFPS() (float64, error) {
+	if fps, ok := timeUnitFPS[u.Time]; ok {
+		return fps, nil
+	}
+	if strings.HasSuffix(u.Time, "fps") {
+		fps, err := strconv.ParseFloat(strings.TrimSuffix(u.Time, "fps"), 64)
+		if err == nil && 0 < fps {
+			return fps, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("%s is an unknown time unit", u.Time))
+}
+
+// getLinearScale returns the scale from centimeters, the internal linear
+// unit, to Linear.
+func (u CurrentUnit) getLinearScale() (float64, error) {
+	if cm, ok := linearUnitCentimeters[u.Linear]; ok {
+		return 1 / cm, nil
+	}
+	return 0, errors.New(fmt.Sprintf("%s is an unknown linear unit", u.Linear))
+}
+
+// getAngleScale returns the scale from radians, the internal angular unit,
+// to Angle.
+func (u CurrentUnit) getAngleScale() float64 {
+	if u.Angle == "radian" || u.Angle == "rad" {
+		return 1
+	}
+	return 180 / math.Pi
+}
@@ -0,0 +1,200 @@
+package mayaascii
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type DependencyKind string
+
+const (
+	DependencyReference       DependencyKind = "reference"
+	DependencyTexture         DependencyKind = "texture"
+	DependencyTextureSequence DependencyKind = "textureSequence"
+	DependencyAlembic         DependencyKind = "alembic"
+	DependencyGpuCache        DependencyKind = "gpuCache"
+	DependencyAudio           DependencyKind = "audio"
+	DependencyImagePlane      DependencyKind = "imagePlane"
+	DependencyXGen            DependencyKind = "xgen"
+	// DependencyFile is an attribute added with "addAttr -uaf".
+	DependencyFile DependencyKind = "file"
+)
+
+var dependencyKinds = map[string]DependencyKind{
+	"file":        DependencyTexture,
+	"AlembicNode": DependencyAlembic,
+	"gpuCache":    DependencyGpuCache,
+	"audio":       DependencyAudio,
+	"imagePlane":  DependencyImagePlane,
+	"xgmPalette":  DependencyXGen,
+}
+
+// Dependency is an external file that the scene depends on.
+type Dependency struct {
+	Kind DependencyKind
+	// Path is the path written in the scene.
+	Path string
+	// Pattern is the path with the "<UDIM>", "<f>" or "#" tokens of a
+	// texture sequence. It is empty for the other kinds.
+	Pattern string
+	// File is the reference. It is nil for the other kinds.
+	File *File
+	// Node and Attr are the owner of the path. They are nil and "" for
+	// a reference.
+	Node   *Node
+	Attr   string
+	LineNo uint
+}
+
+// Dependencies returns every external file that the scene depends on:
+// the references in the order of the file, then the file path attributes
+// (see FilePathAttrs) in the order of the nodes. The nested references of
+// the references are the "file -rdi" prelude entries of depth 2 or more,
+// and the depth 1 entries of the top-level references are not included.
+func (o *Object) Dependencies() []*Dependency {
+	var deps []*Dependency
+	for _, f := range o.Files {
+		if f.IsReferenceDepthInfo() && f.fileCmd.ReferenceDepthInfo <= 1 {
+			continue
+		}
+		deps = append(deps, &Dependency{
+			Kind:   DependencyReference,
+			Path:   f.GetPath(),
+			File:   f,
+			LineNo: f.GetLineNo(),
+		})
+	}
+	for _, a := range o.filePathAttrs() {
+		kind, ok := dependencyKinds[a.node.GetType()]
+		if !ok || !isFilePathAttrOf(a.node.GetType(), a.setAttr.AttrName) {
+			kind = DependencyFile
+		}
+		d := &Dependency{
+			Kind:   kind,
+			Path:   a.getPath(),
+			Node:   a.node,
+			Attr:   a.setAttr.AttrName,
+			LineNo: a.setAttr.LineNo,
+		}
+		if kind == DependencyTexture {
+			d.Pattern = getTexturePattern(a.node, d.Path)
+			if d.Pattern != "" {
+				d.Kind = DependencyTextureSequence
+			}
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
+
+func isFilePathAttrOf(nodeType, name string) bool {
+	for _, attr := range FilePathAttrs[nodeType] {
+		if attr == name {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	sequenceTokenRegexp = regexp.MustCompile(`(?i)<udim>|<u>|<v>|<f>|#+`)
+	// lastNumberRegexp matches the number before the extension.
+	lastNumberRegexp = regexp.MustCompile(`\d+(\.[^./]*)$`)
+)
+
+// getTexturePattern returns the pattern of a UDIM or frame sequence
+// texture, or "".
+func getTexturePattern(n *Node, p string) string {
+	if sequenceTokenRegexp.MatchString(p) {
+		return p
+	}
+	isUDIM := isAttrOn(n, ".uvt", ".uvTilingMode")
+	isSequence := isAttrOn(n, ".ufe", ".useFrameExtension")
+	if !isUDIM && !isSequence {
+		return ""
+	}
+	for _, name := range []string{".cfnp", ".computedFileTextureNamePattern"} {
+		if a := n.GetAttr(name); a != nil && len(a.GetAttrValue()) != 0 {
+			if pattern := unescapeMayaString(a.GetAttrValue()[0].String()); pattern != "" {
+				return pattern
+			}
+		}
+	}
+	token := "<f>"
+	if isUDIM {
+		token = "<UDIM>"
+	}
+	if !lastNumberRegexp.MatchString(p) {
+		return ""
+	}
+	return lastNumberRegexp.ReplaceAllString(p, token+"$1")
+}
+
+// isAttrOn reports whether the bool or int value of the attribute is not
+// false or 0.
+func isAttrOn(n *Node, names ...string) bool {
+	for _, name := range names {
+		a := n.GetAttr(name)
+		if a == nil || len(a.GetAttrValue()) == 0 {
+			continue
+		}
+		switch v := a.GetAttrValue()[0].(type) {
+		case *AttrBool:
+			return v.Bool()
+		case *AttrInt:
+			return v.Int() != 0
+		}
+	}
+	return false
+}
+
+// toGlob converts the tokens of a sequence pattern to a glob pattern. The
+// glob metacharacters of the other parts are escaped.
+func toGlob(pattern string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range sequenceTokenRegexp.FindAllStringIndex(pattern, -1) {
+		b.WriteString(globEscaper.Replace(pattern[last:loc[0]]))
+		token := pattern[loc[0]:loc[1]]
+		switch strings.ToLower(token) {
+		case "<udim>":
+			b.WriteString("1[0-9][0-9][0-9]")
+		case "<u>", "<v>", "<f>":
+			b.WriteString("[0-9]*")
+		default:
+			b.WriteString(strings.Repeat("[0-9]", len(token)))
+		}
+		last = loc[1]
+	}
+	b.WriteString(globEscaper.Replace(pattern[last:]))
+	return b.String()
+}
+
+// globEscaper escapes the glob metacharacters as the character classes,
+// which work with the backslash separators of Windows.
+var globEscaper = strings.NewReplacer("[", "[[]", "*", "[*]", "?", "[?]")
+
+// Expand returns the files of the dependency on the filesystem. The path
+// is resolved like Resolve, and a sequence pattern is expanded to every
+// matched file. An empty result means that the dependency is missing.
+func (r *FileReferenceResolver) Expand(d *Dependency) []string {
+	p := d.Path
+	if d.Pattern != "" {
+		p = d.Pattern
+	}
+	for _, c := range r.Candidates(p) {
+		if !sequenceTokenRegexp.MatchString(c) {
+			if _, err := os.Stat(filepath.FromSlash(c)); err == nil {
+				return []string{filepath.FromSlash(c)}
+			}
+			continue
+		}
+		matches, err := filepath.Glob(filepath.FromSlash(toGlob(c)))
+		if err == nil && len(matches) != 0 {
+			return matches
+		}
+	}
+	return nil
+}
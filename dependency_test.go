@@ -0,0 +1,144 @@
+package mayaascii
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestObject_Dependencies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mayaascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{
+		"char.ma", "a.abc", "custom.abc",
+		"wood.1001.png", "wood.1002.png",
+		"img.0001.png", "img.0002.png", "img.0003.png",
+	} {
+		writeTestMa(t, dir, name, "")
+	}
+
+	mo, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+file -rdi 1 -ns "char" -rfn "charRN" -typ "mayaAscii" "$ROOT/char.ma";
+file -r -ns "char" -dr 1 -rfn "charRN" -typ "mayaAscii" "$ROOT/char.ma";
+createNode file -n "file1";
+	setAttr ".ftn" -type "string" "$ROOT/wood.<UDIM>.png";
+createNode file -n "file2";
+	setAttr ".ftn" -type "string" "$ROOT/img.0001.png";
+	setAttr ".ufe" yes;
+createNode file -n "file3";
+	setAttr ".ftn" -type "string" "$ROOT/wood.1001.png";
+	setAttr ".uvt" 3;
+	setAttr ".cfnp" -type "string" "$ROOT/wood.<UDIM>.png";
+createNode file -n "file4";
+	setAttr ".ftn" -type "string" "$ROOT/plain.png";
+createNode AlembicNode -n "abc1";
+	setAttr ".abc_File" -type "string" "$ROOT/a.abc";
+createNode audio -n "audio1";
+	setAttr ".f" -type "string" "$ROOT/missing.wav";
+createNode transform -n "custom";
+	addAttr -ci true -uaf -sn "cf" -ln "cacheFile" -dt "string";
+	setAttr ".cf" -type "string" "$ROOT/custom.abc";`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver := &FileReferenceResolver{
+		Getenv: func(key string) string {
+			if key == "ROOT" {
+				return filepath.ToSlash(dir)
+			}
+			return ""
+		},
+	}
+
+	deps := mo.Dependencies()
+	wonts := []struct {
+		kind    DependencyKind
+		node    string
+		pattern string
+		files   int
+	}{
+		{DependencyReference, "", "", 1},
+		{DependencyTextureSequence, "file1", "$ROOT/wood.<UDIM>.png", 2},
+		{DependencyTextureSequence, "file2", "$ROOT/img.<f>.png", 3},
+		{DependencyTextureSequence, "file3", "$ROOT/wood.<UDIM>.png", 2},
+		{DependencyTexture, "file4", "", 0},
+		{DependencyAlembic, "abc1", "", 1},
+		{DependencyAudio, "audio1", "", 0},
+		{DependencyFile, "custom", "", 1},
+	}
+	if len(deps) != len(wonts) {
+		t.Fatalf("got len(deps) %d, wont %d", len(deps), len(wonts))
+	}
+	for i, w := range wonts {
+		d := deps[i]
+		node := ""
+		if d.Node != nil {
+			node = d.Node.GetName()
+		}
+		stringTester(stringTestData{"Kind", string(d.Kind), string(w.kind)}, t)
+		stringTester(stringTestData{"Node", node, w.node}, t)
+		stringTester(stringTestData{"Pattern", d.Pattern, w.pattern}, t)
+		intTester(intTestData{string(d.Kind) + " files", len(resolver.Expand(d)), w.files}, t)
+	}
+	stringTester(stringTestData{"deps[0].File", deps[0].File.GetReferenceNode(), "charRN"}, t)
+	intTester(intTestData{"deps[0].LineNo", int(deps[0].LineNo), 3}, t)
+	stringTester(stringTestData{"deps[5].Attr", deps[5].Attr, ".abc_File"}, t)
+}
+
+func TestObject_Dependencies_nested(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+file -rdi 1 -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";
+file -rdi 2 -ns "rig" -rfn "char:rigRN" -typ "mayaAscii" "rig.ma";
+file -rdi 3 -ns "skel" -rfn "char:rig:skelRN" -typ "mayaAscii" "skel.ma";
+file -rdi 1 -ns "prop" -rfn "propRN" -typ "mayaAscii" "prop.ma";
+file -r -ns "char" -dr 1 -rfn "charRN" -typ "mayaAscii" "char.ma";
+file -r -ns "prop" -dr 1 -rfn "propRN" -typ "mayaAscii" "prop.ma";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, d := range mo.Dependencies() {
+		stringTester(stringTestData{d.Path + " Kind", string(d.Kind), string(DependencyReference)}, t)
+		paths = append(paths, d.Path)
+	}
+	stringTester(stringTestData{"Paths", strings.Join(paths, ","),
+		"rig.ma,skel.ma,char.ma,prop.ma"}, t)
+}
+
+func TestFileReferenceResolver_Expand_glob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mayaascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{
+		"tex[v1].png", "tex1.png",
+		"seq[v2].1001.png", "seq[v2].1002.png", "seqv.1001.png",
+	} {
+		writeTestMa(t, dir, name, "")
+	}
+	root := filepath.ToSlash(dir)
+	resolver := &FileReferenceResolver{}
+	for _, d := range []struct {
+		path, pattern string
+		wont          []string
+	}{
+		{root + "/tex[v1].png", "", []string{"tex[v1].png"}},
+		{root + "/tex?.png", "", nil},
+		{root + "/seq[v2].1001.png", root + "/seq[v2].<UDIM>.png",
+			[]string{"seq[v2].1001.png", "seq[v2].1002.png"}},
+	} {
+		var names []string
+		for _, m := range resolver.Expand(&Dependency{Path: d.path, Pattern: d.pattern}) {
+			names = append(names, filepath.Base(m))
+		}
+		stringTester(stringTestData{d.path, strings.Join(names, ","),
+			strings.Join(d.wont, ",")}, t)
+	}
+}
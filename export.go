@@ -0,0 +1,363 @@
+package mayaascii
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// plugInfo is the long name of a short attribute name and its compound.
+type plugInfo struct {
+	fullName string
+	parent   string
+	index    int
+}
+
+var plugInfos = map[string]plugInfo{
+	"tx":  {"translate.translateX", "t", 0},
+	"ty":  {"translate.translateY", "t", 1},
+	"tz":  {"translate.translateZ", "t", 2},
+	"rx":  {"rotate.rotateX", "r", 0},
+	"ry":  {"rotate.rotateY", "r", 1},
+	"rz":  {"rotate.rotateZ", "r", 2},
+	"sx":  {"scale.scaleX", "s", 0},
+	"sy":  {"scale.scaleY", "s", 1},
+	"sz":  {"scale.scaleZ", "s", 2},
+	"v":   {"visibility", "", 0},
+	"fl":  {"focalLength", "", 0},
+	"hfa": {"horizontalFilmAperture", "cap", 0},
+	"vfa": {"verticalFilmAperture", "cap", 1},
+}
+
+var animLinearUnits = map[string]string{
+	"millimeter": "mm",
+	"centimeter": "cm",
+	"meter":      "m",
+	"kilometer":  "km",
+	"inch":       "in",
+	"foot":       "ft",
+	"yard":       "yd",
+	"mile":       "mi",
+}
+
+var animAngularUnits = map[string]string{
+	"degree": "deg",
+	"radian": "rad",
+}
+
+// AnimatedPlug is a plug that an animCurve drives.
+type AnimatedPlug struct {
+	Node *Node
+	// Attr is the attribute name of the connection like "tx".
+	Attr  string
+	Curve *AnimCurve
+}
+
+// GetAnimatedPlugs returns the plugs connected from the animCurves in the
+// order of the connections.
+func (o *Object) GetAnimatedPlugs() ([]*AnimatedPlug, error) {
+	var plugs []*AnimatedPlug
+	for _, ca := range o.connections.source {
+		if ca.SrcAttr != "o" && ca.SrcAttr != "output" {
+			continue
+		}
+		src, ok := o.Nodes[ca.SrcNode]
+		if !ok || src.isDeleted || !strings.HasPrefix(src.GetType(), "animCurve") {
+			continue
+		}
+		dst, ok := o.Nodes[ca.DstNode]
+		if !ok || dst.isDeleted {
+			continue
+		}
+		c, err := src.AsAnimCurve()
+		if err != nil {
+			return nil, err
+		}
+		plugs = append(plugs, &AnimatedPlug{Node: dst, Attr: ca.DstAttr, Curve: c})
+	}
+	return plugs, nil
+}
+
+// ExportAnim writes the animCurves of the nodes, or of all the animated
+// nodes if no nodes, in the .anim format of animImportExport.
+func (o *Object) ExportAnim(w io.Writer, nodes ...string) error {
+	plugs, err := o.GetAnimatedPlugs()
+	if err != nil {
+		return err
+	}
+	if 0 < len(nodes) {
+		names := map[string]bool{}
+		for _, name := range nodes {
+			if _, err := o.GetNode(name); err != nil {
+				return err
+			}
+			names[name] = true
+		}
+		var filtered []*AnimatedPlug
+		for _, p := range plugs {
+			if names[p.Node.GetName()] {
+				filtered = append(filtered, p)
+			}
+		}
+		plugs = filtered
+	}
+
+	u := o.GetCurrentUnit()
+	start, end := math.Inf(1), math.Inf(-1)
+	for _, p := range plugs {
+		if len(p.Curve.Keys) == 0 || !p.Curve.IsTimeInput() {
+			continue
+		}
+		start = math.Min(start, p.Curve.Keys[0].Time)
+		end = math.Max(end, p.Curve.Keys[len(p.Curve.Keys)-1].Time)
+	}
+	if end < start {
+		start, end = 0, 0
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "animVersion 1.1;\n")
+	if v := o.Header().MayaVersion; v != "" {
+		fmt.Fprintf(bw, "mayaVersion %s;\n", v)
+	}
+	fmt.Fprintf(bw, "timeUnit %s;\n", u.Time)
+	fmt.Fprintf(bw, "linearUnit %s;\n", getAnimUnit(animLinearUnits, u.Linear))
+	fmt.Fprintf(bw, "angularUnit %s;\n", getAnimUnit(animAngularUnits, u.Angle))
+	fmt.Fprintf(bw, "startTime %s;\n", formatAnimFloat(start))
+	fmt.Fprintf(bw, "endTime %s;\n", formatAnimFloat(end))
+
+	indices := map[*Node]int{}
+	for _, p := range plugs {
+		fullName, leaf := p.Attr, p.Attr
+		if info, ok := plugInfos[p.Attr]; ok {
+			fullName = info.fullName
+			leaf = fullName[strings.LastIndex(fullName, ".")+1:]
+		}
+		fmt.Fprintf(bw, "anim %s %s %s %d %d %d;\n", fullName, leaf,
+			p.Node.GetName(), getDepth(p.Node), len(p.Node.Children), indices[p.Node])
+		indices[p.Node]++
+		writeAnimData(bw, p.Curve)
+	}
+	return bw.Flush()
+}
+
+func writeAnimData(w io.Writer, c *AnimCurve) {
+	curveType := strings.TrimPrefix(c.Node.GetType(), "animCurve")
+	input := "unitless"
+	if c.IsTimeInput() {
+		input = "time"
+	}
+	output := "unitless"
+	if len(curveType) == 2 {
+		switch curveType[1] {
+		case 'L':
+			output = "linear"
+		case 'A':
+			output = "angular"
+		case 'T':
+			output = "time"
+		}
+	}
+	fmt.Fprintf(w, "animData {\n")
+	fmt.Fprintf(w, "  input %s;\n", input)
+	fmt.Fprintf(w, "  output %s;\n", output)
+	fmt.Fprintf(w, "  weighted %d;\n", boolToInt(c.Weighted))
+	fmt.Fprintf(w, "  preInfinity %s;\n", c.PreInfinity)
+	fmt.Fprintf(w, "  postInfinity %s;\n", c.PostInfinity)
+	fmt.Fprintf(w, "  keys {\n")
+	for _, k := range c.Keys {
+		fmt.Fprintf(w, "    %s %s %s %s %d %d %d",
+			formatAnimFloat(k.Time), formatAnimFloat(k.Value),
+			k.InTangentType, k.OutTangentType,
+			boolToInt(k.TanLocked), boolToInt(k.WeightLocked), boolToInt(k.Breakdown))
+		// The fixed tangents have the angle in degrees and the weight.
+		if k.InTangentType == TangentFixed {
+			fmt.Fprintf(w, " %s %s", formatAnimFloat(getTangentAngle(k.InX, k.InY)),
+				formatAnimFloat(math.Hypot(k.InX, k.InY)))
+		}
+		if k.OutTangentType == TangentFixed {
+			fmt.Fprintf(w, " %s %s", formatAnimFloat(getTangentAngle(k.OutX, k.OutY)),
+				formatAnimFloat(math.Hypot(k.OutX, k.OutY)))
+		}
+		fmt.Fprintf(w, ";\n")
+	}
+	fmt.Fprintf(w, "  }\n}\n")
+}
+
+// ExportChan writes the camera, a camera shape or its transform, in the
+// .chan format of Nuke. A line is "frame tx ty tz rx ry rz vfov" of each
+// frame of fr, or of the playback range if fr is nil. The translation and
+// the rotation in degrees are of the world matrix of the camera, and the
+// rotation is in the ".ro" rotate order of the camera transform, so the
+// rot_order of the Nuke camera, ZXY by default, must be set to it. The
+// vertical field of view in degrees is of the focal length and the
+// vertical film aperture of the frame.
+func (o *Object) ExportChan(w io.Writer, camera string, fr *FrameRange) error {
+	xform, _, err := o.getCamera(camera)
+	if err != nil {
+		return err
+	}
+	ro := 0
+	if values := getNodeAttrFloats(xform, ".ro"); 0 < len(values) {
+		ro = int(values[0])
+	}
+	if ro < 0 || len(rotateOrderList) <= ro {
+		return errors.New(
+			fmt.Sprintf("%s has an invalid rotate order %d", xform.GetName(), ro))
+	}
+	return o.ExportChanWithRotateOrder(w, camera, fr, rotateOrderList[ro])
+}
+
+// ExportChanWithRotateOrder is ExportChan with the rotation in order like
+// RotateOrderZXY, the default rot_order of Nuke.
+func (o *Object) ExportChanWithRotateOrder(
+	w io.Writer, camera string, fr *FrameRange, order AttrRotateOrder) error {
+	xform, shape, err := o.getCamera(camera)
+	if err != nil {
+		return err
+	}
+	if fr == nil {
+		if fr, err = o.GetFrameRange(); err != nil {
+			return err
+		}
+	}
+	by := fr.By
+	if by <= 0 {
+		by = 1
+	}
+	plugs, err := o.GetAnimatedPlugs()
+	if err != nil {
+		return err
+	}
+	// The curves are of the long names not to miss "translateX" of "tx".
+	curves := map[string]*AnimCurve{}
+	for _, p := range plugs {
+		curves[p.Node.GetName()+p.Node.getLongAttrName("."+p.Attr)] = p.Curve
+	}
+	getCurve := func(n *Node, name string) (*AnimCurve, bool) {
+		c, ok := curves[n.GetName()+n.getLongAttrName(name)]
+		return c, ok
+	}
+
+	bw := bufio.NewWriter(w)
+	for i := 0; ; i++ {
+		t := fr.Min + float64(i)*by
+		if fr.Max+by*1e-6 < t {
+			break
+		}
+		get := func(n *Node, name string) []float64 {
+			if c, ok := getCurve(n, name); ok {
+				return []float64{c.Evaluate(t)}
+			}
+			return getNodeAttrFloats(n, name)
+		}
+		m, err := xform.getWorldMatrix(get)
+		if err != nil {
+			return err
+		}
+		r := getEulerRotation(*m, order)
+		values := []float64{t, m[12], m[13], m[14],
+			r.X * 180 / math.Pi, r.Y * 180 / math.Pi, r.Z * 180 / math.Pi}
+		value := func(attr string, def float64) float64 {
+			if c, ok := getCurve(shape, "."+attr); ok {
+				return c.Evaluate(t)
+			}
+			return getStaticValue(shape, attr, def)
+		}
+		fl := value("fl", 35)
+		vfa := value("vfa", 0.94488)
+		vfov := 0.0
+		if fl != 0 {
+			// The film aperture is in inches and the focal length in mm.
+			vfov = 2 * math.Atan(vfa*25.4/(2*fl)) * 180 / math.Pi
+		}
+		values = append(values, vfov)
+		for j, v := range values {
+			if 0 < j {
+				bw.WriteString(" ")
+			}
+			bw.WriteString(formatAnimFloat(v))
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// getCamera returns the transform and the shape of the camera name.
+func (o *Object) getCamera(name string) (*Node, *Node, error) {
+	n, err := o.GetNode(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n.GetType() == "camera" {
+		if n.Parent == nil {
+			return nil, nil, errors.New(fmt.Sprintf("%s has no transform", name))
+		}
+		return n.Parent, n, nil
+	}
+	for _, child := range n.Children {
+		if !child.isDeleted && child.GetType() == "camera" {
+			return n, child, nil
+		}
+	}
+	return nil, nil, errors.New(fmt.Sprintf("%s is not a camera", name))
+}
+
+// getStaticValue returns the setAttr value of attr like "tx" of n, or the
+// value of its compound like "t", or def.
+func getStaticValue(n *Node, attr string, def float64) float64 {
+	if values := getNodeAttrFloats(n, "."+attr); 0 < len(values) {
+		return values[0]
+	}
+	if info, ok := plugInfos[attr]; ok && info.parent != "" {
+		if values := getNodeAttrFloats(n, "."+info.parent); info.index < len(values) {
+			return values[info.index]
+		}
+	}
+	return def
+}
+
+// getNodeAttrFloats returns the numbers of the last setAttr of name.
+func getNodeAttrFloats(n *Node, name string) []float64 {
+	var values []float64
+	for _, a := range n.Attrs {
+		if !a.isDeleted && a.GetName() == name {
+			values = getAttrFloats(a)
+		}
+	}
+	return values
+}
+
+func getDepth(n *Node) int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// getTangentAngle returns the angle in degrees of the tangent x y.
+func getTangentAngle(x, y float64) float64 {
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+
+func getAnimUnit(units map[string]string, unit string) string {
+	if u, ok := units[unit]; ok {
+		return u
+	}
+	return unit
+}
+
+func formatAnimFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
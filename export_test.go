@@ -0,0 +1,202 @@
+package mayaascii
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const exportTestMa = `//Maya ASCII 2019 scene
+requires maya "2019";
+currentUnit -l centimeter -a degree -t ntsc;
+createNode transform -n "camera1";
+	setAttr ".t" -type "double3" 1 2 3 ;
+	setAttr ".r" -type "double3" 0 45 0 ;
+createNode camera -n "cameraShape1" -p "camera1";
+	setAttr ".fl" 50;
+createNode animCurveTL -n "camera1_translateX";
+	setAttr ".tan" 2;
+	setAttr ".wgt" no;
+	setAttr -s 2 ".ktv[0:1]"  1 0 3 10;
+	setAttr ".kot[1]"  1;
+	setAttr ".kox[1]"  1;
+	setAttr ".koy[1]"  0;
+	setAttr ".pst" 1;
+createNode animCurveTA -n "camera1_rotateX";
+	setAttr -s 2 ".ktv[0:1]"  2 0 3 90;
+	setAttr ".kot[0]"  5;
+createNode animCurveTU -n "cameraShape1_focalLength";
+	setAttr -s 1 ".ktv[0]"  1 35;
+connectAttr "camera1_translateX.o" "camera1.tx";
+connectAttr "camera1_rotateX.o" "camera1.rx";
+connectAttr "cameraShape1_focalLength.o" "cameraShape1.fl";
+`
+
+func TestObject_ExportAnim(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(exportTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugs, err := o.GetAnimatedPlugs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"GetAnimatedPlugs", len(plugs), 3}, t)
+
+	var b bytes.Buffer
+	if err := o.ExportAnim(&b, "camera1"); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"ExportAnim", b.String(), `animVersion 1.1;
+mayaVersion 2019;
+timeUnit ntsc;
+linearUnit cm;
+angularUnit deg;
+startTime 1;
+endTime 3;
+anim translate.translateX translateX camera1 0 1 0;
+animData {
+  input time;
+  output linear;
+  weighted 0;
+  preInfinity constant;
+  postInfinity linear;
+  keys {
+    1 0 linear linear 1 1 0;
+    3 10 linear fixed 1 1 0 0 1;
+  }
+}
+anim rotate.rotateX rotateX camera1 0 1 1;
+animData {
+  input time;
+  output angular;
+  weighted 0;
+  preInfinity constant;
+  postInfinity constant;
+  keys {
+    2 0 auto step 1 1 0;
+    3 90 auto auto 1 1 0;
+  }
+}
+`}, t)
+
+	b.Reset()
+	if err := o.ExportAnim(&b); err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"ExportAnim all",
+		strings.Contains(b.String(), "anim focalLength focalLength cameraShape1 1 0 0;\n"), true}, t)
+	if err := o.ExportAnim(&b, "none"); err == nil {
+		t.Errorf("ExportAnim(none) got nil error")
+	}
+}
+
+func TestObject_ExportChan(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(exportTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := o.ExportChan(&b, "cameraShape1", &FrameRange{Min: 1, Max: 4}); err != nil {
+		t.Fatal(err)
+	}
+	vfov := 2 * math.Atan(0.94488*25.4/(2*35)) * 180 / math.Pi
+	wonts := [][]float64{
+		{1, 0, 2, 3, 0, 45, 0, vfov},
+		{2, 5, 2, 3, 0, 45, 0, vfov},
+		{3, 10, 2, 3, 90, 45, 0, vfov},
+		{4, 10, 2, 3, 90, 45, 0, vfov},
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	intTester(intTestData{"lines", len(lines), len(wonts)}, t)
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		intTester(intTestData{"fields", len(fields), 8}, t)
+		for j, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			floatTester(floatTestData{line, v, wonts[i][j]}, t)
+		}
+	}
+
+	// Without the playback range.
+	if err := o.ExportChan(&b, "camera1", nil); err == nil {
+		t.Errorf("ExportChan() without sceneConfigurationScriptNode got nil error")
+	}
+	if err := o.ExportChan(&b, "camera1_translateX", &FrameRange{Min: 1, Max: 1}); err == nil {
+		t.Errorf("ExportChan(camera1_translateX) got nil error")
+	}
+}
+
+const exportParentedTestMa = `currentUnit -l centimeter -a degree -t film;
+createNode transform -n "rig";
+	setAttr ".t" -type "double3" 0 0 10 ;
+	setAttr ".r" -type "double3" 0 0 45 ;
+createNode transform -n "camera1" -p "rig";
+	setAttr ".tx" 1;
+	setAttr ".rx" 30;
+createNode camera -n "cameraShape1" -p "camera1";
+	setAttr ".fl" 35;
+createNode animCurveTL -n "rig_translateZ";
+	setAttr ".tan" 2;
+	setAttr -s 2 ".ktv[0:1]"  1 10 2 20;
+connectAttr "rig_translateZ.o" "rig.tz";
+`
+
+func TestObject_ExportChan_parented(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(exportParentedTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfov := 2 * math.Atan(0.94488*25.4/(2*35)) * 180 / math.Pi
+	for _, d := range []struct {
+		title string
+		order AttrRotateOrder
+		wonts [][]float64
+	}{
+		{"XYZ", RotateOrderXYZ, [][]float64{
+			{1, math.Sqrt2 / 2, math.Sqrt2 / 2, 10, 30, 0, 45, vfov},
+			{2, math.Sqrt2 / 2, math.Sqrt2 / 2, 20, 30, 0, 45, vfov},
+		}},
+		{"ZXY", RotateOrderZXY, [][]float64{
+			{1, math.Sqrt2 / 2, math.Sqrt2 / 2, 10,
+				20.704811054635428, 22.20765429859648, 49.10660535086909, vfov},
+		}},
+	} {
+		var b bytes.Buffer
+		fr := &FrameRange{Min: 1, Max: float64(len(d.wonts))}
+		if err := o.ExportChanWithRotateOrder(&b, "camera1", fr, d.order); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		intTester(intTestData{d.title + " lines", len(lines), len(d.wonts)}, t)
+		for i, line := range lines {
+			for j, f := range strings.Fields(line) {
+				v, err := strconv.ParseFloat(f, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+				floatTester(floatTestData{d.title + " " + line, v, d.wonts[i][j]}, t)
+			}
+		}
+	}
+
+	// ExportChan is in the rotate order of the camera.
+	o, err = Unmarshal(strings.NewReader(strings.Replace(exportParentedTestMa,
+		"\tsetAttr \".rx\" 30;", "\tsetAttr \".rx\" 30;\n\tsetAttr \".ro\" 2;", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := o.ExportChan(&b, "camera1", &FrameRange{Min: 1, Max: 1}); err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(b.String())
+	intTester(intTestData{"ro fields", len(fields), 8}, t)
+	v, _ := strconv.ParseFloat(fields[4], 64)
+	floatTester(floatTestData{"ro rx", v, 20.704811054635428}, t)
+}
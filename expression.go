@@ -0,0 +1,190 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UnitConversion is the ".uno" attribute of expression, the "-uc" flag of
+// the expression command.
+type UnitConversion int
+
+const (
+	UnitConversionAll UnitConversion = iota
+	UnitConversionNone
+	UnitConversionAngularOnly
+)
+
+func (u UnitConversion) String() string {
+	switch u {
+	case UnitConversionNone:
+		return "none"
+	case UnitConversionAngularOnly:
+		return "angularOnly"
+	}
+	return "all"
+}
+
+// ExpressionBinding is a plug connected to ".in[Index]" or from
+// ".out[Index]" of expression.
+type ExpressionBinding struct {
+	Index int
+	// Plug is "node.attr" like "pCube1.tx". A unitConversion node between
+	// them is skipped.
+	Plug string
+	// ConversionFactor is the ".cf" of the unitConversion node between
+	// them, or 0.
+	ConversionFactor float64
+}
+
+// Expression is an expression node with the decoded source.
+type Expression struct {
+	Node *Node
+	// Source is the ".ixp" that refers the inputs and the outputs as
+	// ".I[0]" and ".O[0]".
+	Source string
+	// Object is the node connected to ".obm", the default object of the
+	// expression, or "".
+	Object         string
+	UnitConversion UnitConversion
+	Inputs         []*ExpressionBinding
+	// Outputs has a binding for each destination of ".out[Index]".
+	Outputs []*ExpressionBinding
+}
+
+var expressionPlugRegexp = regexp.MustCompile(`\.([IO])\[(\d+)\]`)
+
+// GetReadableSource returns Source with ".I[0]" and ".O[0]" replaced by
+// the bound plugs.
+func (e *Expression) GetReadableSource() string {
+	return expressionPlugRegexp.ReplaceAllStringFunc(e.Source, func(m string) string {
+		sub := expressionPlugRegexp.FindStringSubmatch(m)
+		bindings := e.Inputs
+		if sub[1] == "O" {
+			bindings = e.Outputs
+		}
+		i, _ := strconv.Atoi(sub[2])
+		for _, b := range bindings {
+			if b.Index == i {
+				return b.Plug
+			}
+		}
+		return m
+	})
+}
+
+// GetExpressions returns the expression nodes of o in the order of the
+// file.
+func (o *Object) GetExpressions() []*Expression {
+	var expressions []*Expression
+	for _, n := range o.sortedNodes() {
+		if n.isDeleted || n.GetType() != "expression" {
+			continue
+		}
+		expressions = append(expressions, o.newExpression(n))
+	}
+	return expressions
+}
+
+// GetExpression returns the expression node of name.
+func (o *Object) GetExpression(name string) (*Expression, error) {
+	n, err := o.GetNode(name)
+	if err != nil {
+		return nil, err
+	}
+	if n.isDeleted || n.GetType() != "expression" {
+		return nil, errors.New(fmt.Sprintf("%s is not an expression", name))
+	}
+	return o.newExpression(n), nil
+}
+
+func (o *Object) newExpression(n *Node) *Expression {
+	attrs := o.getNodeAttrs(n)
+	e := &Expression{
+		Node:           n,
+		Source:         getStringAttr(attrs, ".ixp", ".internalExpression"),
+		UnitConversion: UnitConversion(getIntAttr(attrs, ".uno", ".unitOption")),
+	}
+	name := n.GetName()
+	for _, ca := range o.connections.source {
+		switch {
+		case ca.DstNode == name && (ca.DstAttr == "obm" || ca.DstAttr == "objectMsg"):
+			e.Object = ca.SrcNode
+		case ca.DstNode == name:
+			if i, ok := getPlugIndex(ca.DstAttr, "in", "input"); ok {
+				plug, cf := o.getUnitConversionSrc(ca)
+				e.Inputs = append(e.Inputs,
+					&ExpressionBinding{Index: i, Plug: plug, ConversionFactor: cf})
+			}
+		case ca.SrcNode == name:
+			if i, ok := getPlugIndex(ca.SrcAttr, "out", "output"); ok {
+				for _, dst := range o.getUnitConversionDsts(ca) {
+					dst.Index = i
+					e.Outputs = append(e.Outputs, dst)
+				}
+			}
+		}
+	}
+	return e
+}
+
+// getUnitConversionSrc returns the source plug of ca, or the source plug
+// of the unitConversion node of ca and its factor.
+func (o *Object) getUnitConversionSrc(ca *ConnectAttrCmd) (string, float64) {
+	if uc := o.getUnitConversionNode(ca.SrcNode); uc != nil {
+		for _, c := range o.connections.source {
+			if c.DstNode == uc.GetName() && (c.DstAttr == "i" || c.DstAttr == "input") {
+				return c.SrcNode + "." + c.SrcAttr, getFloatAttr(o.getNodeAttrs(uc), ".cf", ".conversionFactor")
+			}
+		}
+	}
+	return ca.SrcNode + "." + ca.SrcAttr, 0
+}
+
+// getUnitConversionDsts returns the destination plug of ca, or the
+// destination plugs of the unitConversion node of ca.
+func (o *Object) getUnitConversionDsts(ca *ConnectAttrCmd) []*ExpressionBinding {
+	uc := o.getUnitConversionNode(ca.DstNode)
+	if uc == nil {
+		return []*ExpressionBinding{{Plug: ca.DstNode + "." + ca.DstAttr}}
+	}
+	var dsts []*ExpressionBinding
+	cf := getFloatAttr(o.getNodeAttrs(uc), ".cf", ".conversionFactor")
+	for _, c := range o.connections.source {
+		if c.SrcNode == uc.GetName() && (c.SrcAttr == "o" || c.SrcAttr == "output") {
+			dsts = append(dsts,
+				&ExpressionBinding{Plug: c.DstNode + "." + c.DstAttr, ConversionFactor: cf})
+		}
+	}
+	return dsts
+}
+
+func (o *Object) getUnitConversionNode(name string) *Node {
+	n, ok := o.Nodes[name]
+	if !ok || n.isDeleted || n.GetType() != "unitConversion" {
+		return nil
+	}
+	return n
+}
+
+// getPlugIndex returns 2 of "in[2]" if the name is one of names.
+func getPlugIndex(attr string, names ...string) (int, bool) {
+	open := strings.Index(attr, "[")
+	if open == -1 || !strings.HasSuffix(attr, "]") {
+		return 0, false
+	}
+	for _, name := range names {
+		if attr[:open] != name {
+			continue
+		}
+		i, err := strconv.Atoi(attr[open+1 : len(attr)-1])
+		if err != nil {
+			return 0, false
+		}
+		return i, true
+	}
+	return 0, false
+}
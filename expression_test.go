@@ -0,0 +1,63 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+const expressionTestMa = `createNode transform -n "pCube1";
+createNode transform -n "pCube2";
+createNode expression -n "expression1";
+	setAttr -k on ".nds";
+	setAttr -s 2 ".in";
+	setAttr ".ixp" -type "string" ".O[0] = .I[0] * 2;\n.O[1] = .I[1] + frame;";
+	setAttr ".uno" 2;
+createNode unitConversion -n "unitConversion1";
+	setAttr ".cf" 57.295779513082323;
+createNode unitConversion -n "unitConversion2";
+	setAttr ".cf" 0.017453292519943295;
+connectAttr "pCube1.tx" "expression1.in[0]";
+connectAttr "unitConversion1.o" "expression1.in[1]";
+connectAttr "pCube1.rx" "unitConversion1.i";
+connectAttr ":time1.o" "expression1.tim";
+connectAttr "pCube2.msg" "expression1.obm";
+connectAttr "expression1.out[0]" "pCube2.ty";
+connectAttr "expression1.out[1]" "unitConversion2.i";
+connectAttr "unitConversion2.o" "pCube2.ry";
+`
+
+func TestObject_GetExpression(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(expressionTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len", len(o.GetExpressions()), 1}, t)
+	if _, err := o.GetExpression("pCube1"); err == nil {
+		t.Errorf("GetExpression(pCube1) got nil error")
+	}
+	e, err := o.GetExpression("expression1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Source", e.Source,
+		".O[0] = .I[0] * 2;\n.O[1] = .I[1] + frame;"}, t)
+	stringTester(stringTestData{"Object", e.Object, "pCube2"}, t)
+	stringTester(stringTestData{"UnitConversion", e.UnitConversion.String(), "angularOnly"}, t)
+
+	intTester(intTestData{"Inputs", len(e.Inputs), 2}, t)
+	stringTester(stringTestData{"Inputs[0]", e.Inputs[0].Plug, "pCube1.tx"}, t)
+	stringTester(stringTestData{"Inputs[1]", e.Inputs[1].Plug, "pCube1.rx"}, t)
+	intTester(intTestData{"Inputs[1].Index", e.Inputs[1].Index, 1}, t)
+	boolTester(boolTestData{"Inputs[1].ConversionFactor",
+		e.Inputs[1].ConversionFactor == 57.295779513082323, true}, t)
+
+	intTester(intTestData{"Outputs", len(e.Outputs), 2}, t)
+	stringTester(stringTestData{"Outputs[0]", e.Outputs[0].Plug, "pCube2.ty"}, t)
+	boolTester(boolTestData{"Outputs[0].ConversionFactor",
+		e.Outputs[0].ConversionFactor == 0, true}, t)
+	stringTester(stringTestData{"Outputs[1]", e.Outputs[1].Plug, "pCube2.ry"}, t)
+	intTester(intTestData{"Outputs[1].Index", e.Outputs[1].Index, 1}, t)
+
+	stringTester(stringTestData{"GetReadableSource", e.GetReadableSource(),
+		"pCube2.ty = pCube1.tx * 2;\npCube2.ry = pCube1.rx + frame;"}, t)
+}
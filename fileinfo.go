@@ -0,0 +1,62 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GetFileInfo returns the value of the fileInfo key. The escapes of the
+// value like "\n" are unescaped.
+func (o *Object) GetFileInfo(key string) (string, error) {
+	fi, _ := o.findFileInfo(key)
+	if fi == nil {
+		return "", errors.New(fmt.Sprintf("%s fileInfo was not found", key))
+	}
+	return unescapeMayaString(fi.GetValue()), nil
+}
+
+// SetFileInfo sets the value of the fileInfo key, or adds the fileInfo
+// after the last fileInfo or requires. The quotes, the backslashes and
+// the line breaks of value are escaped.
+func (o *Object) SetFileInfo(key, value string) error {
+	if key == "" {
+		return errors.New("fileInfo key is empty")
+	}
+	fic := &FileInfoCmd{Name: escapeMayaString(key), Value: escapeMayaString(value)}
+	if fi, _ := o.findFileInfo(key); fi != nil {
+		fi.fileInfoCmd.Value = fic.Value
+		fi.fileInfoCmd.retokenize(fi.fileInfoCmd.String())
+		return nil
+	}
+	fic.Cmd = NewCmd(fic.String())
+	i := len(o.headerComments())
+	for j, c := range o.cmds {
+		if c.Type == TypeFileInfo || c.Type == TypeRequires {
+			i = j + 1
+		}
+	}
+	o.insertCmd(i, fic.Cmd)
+	o.FileInfos = append(o.FileInfos, &FileInfo{fileInfoCmd: fic})
+	return nil
+}
+
+// DeleteFileInfo removes the fileInfo key.
+func (o *Object) DeleteFileInfo(key string) error {
+	fi, i := o.findFileInfo(key)
+	if fi == nil {
+		return errors.New(fmt.Sprintf("%s fileInfo was not found", key))
+	}
+	o.removeCmd(fi.fileInfoCmd.Cmd)
+	o.FileInfos = append(o.FileInfos[:i], o.FileInfos[i+1:]...)
+	return nil
+}
+
+// findFileInfo returns the fileInfo key and its index in FileInfos.
+func (o *Object) findFileInfo(key string) (*FileInfo, int) {
+	for i, fi := range o.FileInfos {
+		if unescapeMayaString(fi.GetName()) == key {
+			return fi, i
+		}
+	}
+	return nil, -1
+}
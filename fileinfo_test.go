@@ -0,0 +1,77 @@
+package mayaascii
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestObject_GetFileInfo(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	osv, err := o.GetFileInfo("osv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"osv",
+		osv, "Microsoft Windows 8 Home Premium Edition, 64-bit  (Build 9200)\n"}, t)
+	_, err = o.GetFileInfo("none")
+	boolTester(boolTestData{"none", err != nil, true}, t)
+}
+
+func TestObject_SetFileInfo(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := "C:\\assets\\\"chr\"\nv2\t;"
+	if err := o.SetFileInfo("assetId", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.SetFileInfo("product", "Maya 2020"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.SetFileInfo("", "x"); err == nil {
+		t.Error("empty key wont error")
+	}
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	stringTester(stringTestData{"product", lines[8], "fileInfo \"product\" \"Maya 2020\";"}, t)
+	stringTester(stringTestData{"assetId", lines[12],
+		"fileInfo \"assetId\" \"C:\\\\assets\\\\\\\"chr\\\"\\nv2\\t;\";"}, t)
+
+	saved, err := Unmarshal(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"FileInfos", len(saved.FileInfos), 6}, t)
+	got, err := saved.GetFileInfo("assetId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"assetId", got, value}, t)
+}
+
+func TestObject_DeleteFileInfo(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.DeleteFileInfo("cutIdentifier"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.DeleteFileInfo("cutIdentifier"); err == nil {
+		t.Error("deleted fileInfo wont be found")
+	}
+	intTester(intTestData{"FileInfos", len(o.FileInfos), 4}, t)
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"Marshal", strings.Contains(b.String(), "cutIdentifier"), false}, t)
+}
@@ -0,0 +1,80 @@
+package mayaascii
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func FuzzUnmarshal(f *testing.F) {
+	f.Add("//Maya ASCII 2018 scene\nrequires maya \"2018\";\ncreateNode transform -n \"a\";\n\tsetAttr \".t\" -type \"double3\" 1 2 3 ;\n")
+	f.Add("createNode mesh -n \"m\" -p \"a\";\n\tsetAttr -s 2 \".vt[0:1]\" 0 0 0 1 1 1;\n\tsetAttr \".fc[0]\" -type \"polyFaces\" f 3 0 1 2 mu 0 3 0 1 2;\n")
+	f.Add("createNode reference -n \"rRN\";\n\tsetAttr \".ed\" -type \"dataReferenceEdits\" \"rRN\" \"\" 5 3 \"rRN\" \"|a.t\" \"-type \\\"double3\\\" 1 2 3\";\n")
+	f.Add("select -ne :time1;\n\taddAttr -ci true -sn \"x\" -ln \"x\" -at \"double\";\nconnectAttr \"a.t\" \"b.t\";\nfileInfo \"a\" \"b\";\n")
+	f.Fuzz(func(t *testing.T, src string) {
+		if o, err := Unmarshal(strings.NewReader(src)); err == nil {
+			for _, n := range o.Nodes {
+				for _, a := range n.Attrs {
+					a.GetAttrValue()
+				}
+			}
+			o.Dependencies()
+		}
+		if o, err := UnmarshalLazy(strings.NewReader(src)); err == nil {
+			for _, n := range o.Nodes {
+				for _, a := range n.Attrs {
+					a.GetAttrValue()
+				}
+			}
+		}
+		s := NewScanner(strings.NewReader(src))
+		for {
+			if _, err := s.Next(); err == io.EOF {
+				break
+			}
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("file -rdi 1 -ns \"a\" -rfn \"aRN\" -op \"v=0;\" -typ \"mayaAscii\" \"a.ma\";")
+	f.Add("workspace -fr \"scene\" \"scenes\";")
+	f.Add("connectAttr \"a.t\" \"b.t\" -na;")
+	f.Add("setAttr -k on -s 2 \".pt[0:1]\" -type \"float3\" 1 2 3 4 5 6;")
+	f.Add("addAttr -ci true -k true -sn \"x\" -ln \"x\" -min 0 -max 1 -at \"double\" -p \"y\";")
+	f.Fuzz(func(t *testing.T, raw string) {
+		ParseLineComment(NewCmd(raw))
+		ParseBlockComment(NewCmd(raw))
+		ParseFile(NewCmd(raw))
+		ParseFileInfo(NewCmd(raw))
+		ParseWorkspace(NewCmd(raw))
+		ParseRequires(NewCmd(raw))
+		ParseConnectAttr(NewCmd(raw))
+		ParseCreateNode(NewCmd(raw))
+		ParseRename(NewCmd(raw))
+		ParseSelect(NewCmd(raw))
+		ParseAddAttr(NewCmd(raw))
+		if sa, err := ParseSetAttr(NewCmd(raw), nil); err == nil {
+			ParseSetAttr(NewCmd(raw), sa)
+		}
+	})
+}
+
+func FuzzParseAttr(f *testing.F) {
+	f.Add("2 0 0 1 1", 0, uint(1), int(SetAttrTypePolyFaces))
+	f.Add("\"rRN\" \"\" 5 3 \"rRN\" \"|a.t\" \"-type \\\"double3\\\" 1 2 3\"", 0, uint(1), int(SetAttrTypeDataReferenceEdits))
+	f.Add("1 3 0 no 3 4 6 0 0 0 1 2 3 4 0 0 1 1 -1 1 0 0 1", 0, uint(1), int(SetAttrTypeNurbsCurve))
+	f.Fuzz(func(t *testing.T, raw string, start int, size uint, attrType int) {
+		token := NewCmd(raw).Token
+		if start < 0 || len(token) < start {
+			return
+		}
+		if size > 1024 {
+			size = 1024
+		}
+		at := SetAttrType(attrType)
+		ParseAttr(&token, start, &size, at)
+		ParseAttrType(&token, start)
+		MakeDataReferenceEdits(&token, start)
+	})
+}
@@ -1,9 +1,9 @@
 module github.com/nrtkbb/go-mayaascii
 
-go 1.14
+go 1.18
 
 require (
+	github.com/klauspost/compress v1.15.15
 	github.com/nrtkbb/bufscan v0.0.0-20180904071105-4f357052231d
-	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9 // indirect
-	golang.org/x/tools v0.1.7 // indirect
+	golang.org/x/text v0.3.8
 )
@@ -0,0 +1,203 @@
+package mayaascii
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LastModifiedLayout is the time layout of "//Last modified:".
+const LastModifiedLayout = "Mon, Jan 2, 2006 03:04:05 PM"
+
+// The keys of the header comments like "//Name: test.ma".
+const (
+	headerName         = "Name"
+	headerLastModified = "Last modified"
+	headerCodeset      = "Codeset"
+)
+
+// The keys of fileInfo written by Maya.
+const (
+	FileInfoApplication   = "application"
+	FileInfoProduct       = "product"
+	FileInfoVersion       = "version"
+	FileInfoCutIdentifier = "cutIdentifier"
+	FileInfoOSV           = "osv"
+	FileInfoUUID          = "UUID"
+	FileInfoLicense       = "license"
+)
+
+// Header is the metadata of the scene in the header comments and the
+// fileInfo commands.
+//
+//	//Maya ASCII 2019 scene
+//	//Name: test.ma
+//	//Last modified: Thu, Oct 1, 2019 01:00:00 AM
+//	//Codeset: UTF-8
+//	...
+//	fileInfo "application" "maya";
+type Header struct {
+	// MayaVersion is "2019" of "//Maya ASCII 2019 scene", or the
+	// fileInfo "version" without the header comment.
+	MayaVersion  string
+	FileName     string
+	LastModified time.Time
+	Codeset      string
+
+	Application   string
+	Product       string
+	CutIdentifier string
+	// OS is the fileInfo "osv" without the line break.
+	OS      string
+	UUID    string
+	License string
+}
+
+// Header returns the metadata of the scene. LastModified is the zero time
+// if the header has no time or the time can't be parsed.
+func (o *Object) Header() Header {
+	var h Header
+	for _, c := range o.headerComments() {
+		comment := getHeaderComment(c)
+		if v, ok := getMayaASCIIVersion(comment); ok {
+			h.MayaVersion = v
+			continue
+		}
+		key, value, ok := splitHeaderComment(comment)
+		if !ok {
+			continue
+		}
+		switch key {
+		case headerName:
+			h.FileName = value
+		case headerLastModified:
+			if t, err := time.ParseInLocation(LastModifiedLayout, value, time.Local); err == nil {
+				h.LastModified = t
+			}
+		case headerCodeset:
+			h.Codeset = value
+		}
+	}
+	for _, fi := range o.FileInfos {
+		value := unescapeMayaString(fi.GetValue())
+		switch fi.GetName() {
+		case FileInfoVersion:
+			if h.MayaVersion == "" {
+				h.MayaVersion = value
+			}
+		case FileInfoApplication:
+			h.Application = value
+		case FileInfoProduct:
+			h.Product = value
+		case FileInfoCutIdentifier:
+			h.CutIdentifier = value
+		case FileInfoOSV:
+			h.OS = strings.TrimRight(value, "\r\n")
+		case FileInfoUUID:
+			h.UUID = value
+		case FileInfoLicense:
+			h.License = value
+		}
+	}
+	return h
+}
+
+// SetHeader writes the fields of h that differ from Header to the header
+// comments and the fileInfo commands, adding them if they don't exist.
+// The empty fields are not written. When the codeset changes, Marshal
+// writes the scene in the new codeset.
+func (o *Object) SetHeader(h Header) {
+	before := o.Header()
+	if h.MayaVersion != "" && h.MayaVersion != before.MayaVersion {
+		o.setHeaderComment("Maya ASCII ", fmt.Sprintf("Maya ASCII %s scene", h.MayaVersion))
+		o.SetFileInfo(FileInfoVersion, h.MayaVersion)
+	}
+	if h.FileName != "" && h.FileName != before.FileName {
+		o.setHeaderComment(headerName+":", headerName+": "+h.FileName)
+	}
+	if !h.LastModified.IsZero() && !h.LastModified.Equal(before.LastModified) {
+		o.setHeaderComment(headerLastModified+":",
+			headerLastModified+": "+h.LastModified.Format(LastModifiedLayout))
+	}
+	if h.Codeset != "" && h.Codeset != before.Codeset {
+		o.setHeaderComment(headerCodeset+":", headerCodeset+": "+h.Codeset)
+		o.codeset = h.Codeset
+	}
+	for _, fi := range []struct {
+		name, value, before string
+	}{
+		{FileInfoApplication, h.Application, before.Application},
+		{FileInfoProduct, h.Product, before.Product},
+		{FileInfoCutIdentifier, h.CutIdentifier, before.CutIdentifier},
+		{FileInfoOSV, h.OS, before.OS},
+		{FileInfoUUID, h.UUID, before.UUID},
+		{FileInfoLicense, h.License, before.License},
+	} {
+		if fi.value != "" && fi.value != fi.before {
+			o.SetFileInfo(fi.name, fi.value)
+		}
+	}
+}
+
+// headerComments returns the line comments at the head of the file.
+func (o *Object) headerComments() []*Cmd {
+	var comments []*Cmd
+	for _, c := range o.cmds {
+		if c.Type != TypeLineComment {
+			break
+		}
+		comments = append(comments, c)
+	}
+	return comments
+}
+
+// getHeaderComment returns the comment of c without "//".
+func getHeaderComment(c *Cmd) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(c.Raw), "//"))
+}
+
+// getMayaASCIIVersion returns "2019" of "Maya ASCII 2019 scene".
+func getMayaASCIIVersion(comment string) (string, bool) {
+	if !strings.HasPrefix(comment, "Maya ASCII ") {
+		return "", false
+	}
+	v := strings.TrimPrefix(comment, "Maya ASCII ")
+	return strings.TrimSuffix(v, " scene"), true
+}
+
+// splitHeaderComment splits "Name: test.ma" into "Name" and "test.ma".
+func splitHeaderComment(comment string) (string, string, bool) {
+	i := strings.Index(comment, ":")
+	if i == -1 {
+		return "", "", false
+	}
+	return comment[:i], strings.TrimSpace(comment[i+1:]), true
+}
+
+// setHeaderComment replaces the header comment that starts with prefix
+// by comment, or adds it after the header comments. "Maya ASCII" is added
+// at the head.
+func (o *Object) setHeaderComment(prefix, comment string) {
+	comments := o.headerComments()
+	for _, c := range comments {
+		if strings.HasPrefix(getHeaderComment(c), prefix) {
+			c.retokenize("//" + comment)
+			for _, lc := range o.LineComments {
+				if lc.lineCommentCmd.Cmd == c {
+					*lc.lineCommentCmd = *ParseLineComment(c)
+				}
+			}
+			return
+		}
+	}
+	c := NewCmd("//" + comment)
+	if strings.HasPrefix(comment, "Maya ASCII ") {
+		// The first line of the file.
+		o.insertCmd(0, c)
+	} else {
+		o.insertCmd(len(comments), c)
+	}
+	o.LineComments = append(o.LineComments, &LineComment{
+		lineCommentCmd: ParseLineComment(c),
+	})
+}
@@ -0,0 +1,91 @@
+package mayaascii
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObject_Header(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := o.Header()
+	stringTester(stringTestData{"MayaVersion", h.MayaVersion, "2019"}, t)
+	stringTester(stringTestData{"FileName", h.FileName, "test.ma"}, t)
+	stringTester(stringTestData{"LastModified",
+		h.LastModified.Format(time.RFC3339),
+		time.Date(2019, 10, 1, 1, 0, 0, 0, time.Local).Format(time.RFC3339)}, t)
+	stringTester(stringTestData{"Codeset", h.Codeset, "UTF-8"}, t)
+	stringTester(stringTestData{"Application", h.Application, "maya"}, t)
+	stringTester(stringTestData{"Product", h.Product, "Maya 2018"}, t)
+	stringTester(stringTestData{"CutIdentifier", h.CutIdentifier, "xxxx"}, t)
+	stringTester(stringTestData{"OS", h.OS,
+		"Microsoft Windows 8 Home Premium Edition, 64-bit  (Build 9200)"}, t)
+	stringTester(stringTestData{"UUID", h.UUID, ""}, t)
+}
+
+func TestObject_SetHeader(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := o.Header()
+	h.MayaVersion = "2020"
+	h.LastModified = time.Date(2020, 1, 2, 15, 4, 5, 0, time.Local)
+	h.Product = "Maya \"2020\""
+	h.UUID = "9A1B2C3D-0000-1111-2222-333344445555"
+	o.SetHeader(h)
+
+	stringTester(stringTestData{"Header", o.Header().Product, h.Product}, t)
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	stringTester(stringTestData{"Maya ASCII", lines[0], "//Maya ASCII 2020 scene"}, t)
+	stringTester(stringTestData{"Last modified", lines[2], "//Last modified: Thu, Jan 2, 2020 03:04:05 PM"}, t)
+	stringTester(stringTestData{"product", lines[8], "fileInfo \"product\" \"Maya \\\"2020\\\"\";"}, t)
+	stringTester(stringTestData{"version", lines[9], "fileInfo \"version\" \"2020\";"}, t)
+	stringTester(stringTestData{"UUID", lines[12], "fileInfo \"UUID\" \"9A1B2C3D-0000-1111-2222-333344445555\";"}, t)
+
+	o, err = Unmarshal(strings.NewReader("createNode transform -n \"a\";\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.SetHeader(Header{MayaVersion: "2019", FileName: "a.ma", Application: "maya"})
+	b.Reset()
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"new header", b.String(), "//Maya ASCII 2019 scene\n" +
+		"//Name: a.ma\n" +
+		"fileInfo \"version\" \"2019\";\n" +
+		"fileInfo \"application\" \"maya\";\n" +
+		"createNode transform -n \"a\";\n"}, t)
+}
+
+func TestObject_Save(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"saved.ma", "saved.ma.gz", "saved.ma.zst"} {
+		path := filepath.Join(t.TempDir(), name)
+		before := time.Now().Add(-time.Second)
+		if err := o.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		saved, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := saved.Header()
+		stringTester(stringTestData{name + " FileName", h.FileName, "saved.ma"}, t)
+		boolTester(boolTestData{name + " LastModified", h.LastModified.After(before), true}, t)
+		intTester(intTestData{name + " Nodes", len(saved.Nodes), len(o.Nodes)}, t)
+	}
+}
@@ -0,0 +1,142 @@
+package mayaascii
+
+import (
+	"strings"
+	"sync"
+)
+
+// lazySetAttrs is the setAttr commands of a node or a select that are
+// tokenized and decoded together on first access, because each setAttr
+// inherits the values of the previous one like ".vt[500:999]" after
+// ".vt[0:499]". The commands keep only Raw until then.
+type lazySetAttrs struct {
+	once sync.Once
+	// owner is the Attrs of the node or the select.
+	owner *[]*Attr
+	attrs []*Attr
+}
+
+// decode decodes the setAttr commands like Unmarshal. A setAttr that
+// can't be decoded and the rest are removed from the owner with the error.
+func (l *lazySetAttrs) decode() {
+	var beforeSetAttr *SetAttrCmd
+	for i, a := range l.attrs {
+		sa := a.attrCmd.(*SetAttrCmd)
+		if sa.Cmd.Token == nil {
+			sa.Cmd.retokenize(sa.Cmd.Raw)
+		}
+		parsed, err := ParseSetAttr(sa.Cmd, beforeSetAttr)
+		if err != nil {
+			l.drop(l.attrs[i:], err)
+			return
+		}
+		*sa = *parsed
+		beforeSetAttr = sa
+	}
+}
+
+// drop removes attrs from the owner like Unmarshal that stops at the
+// error, and sets the error to them.
+func (l *lazySetAttrs) drop(attrs []*Attr, err error) {
+	dropped := map[*Attr]bool{}
+	for _, a := range attrs {
+		a.err = err
+		dropped[a] = true
+	}
+	var kept []*Attr
+	for _, a := range *l.owner {
+		if !dropped[a] {
+			kept = append(kept, a)
+		}
+	}
+	*l.owner = kept
+}
+
+// decode decodes the value of the setAttr loaded by UnmarshalLazy.
+func (a *Attr) decode() {
+	if a.lazy != nil {
+		a.lazy.once.Do(a.lazy.decode)
+	}
+}
+
+// getSetAttr returns the decoded setAttr command of a.
+func (a *Attr) getSetAttr() (*SetAttrCmd, bool) {
+	a.decode()
+	sa, ok := a.attrCmd.(*SetAttrCmd)
+	return sa, ok
+}
+
+// newLazySetAttr returns the Attr of CurCmd whose value is decoded on
+// first access. owner is the Attrs of the node or the select so far.
+func (p *Parser) newLazySetAttr(node *Node, owner *[]*Attr, attrName string) *Attr {
+	a := &Attr{
+		Node:    node,
+		attrCmd: &SetAttrCmd{Cmd: p.CurCmd, AttrName: attrName},
+	}
+	if 0 < len(*owner) {
+		a.lazy = (*owner)[len(*owner)-1].lazy
+	}
+	if a.lazy == nil {
+		a.lazy = &lazySetAttrs{owner: owner}
+	}
+	a.lazy.attrs = append(a.lazy.attrs, a)
+	return a
+}
+
+// parseLazySetAttr returns the setAttr command of c with Raw and without
+// the tokens, or false if c is not a setAttr.
+func (c *CmdBuilder) parseLazySetAttr() (*Cmd, bool) {
+	if c.IsClear() || !TypeSetAttr.HasPrefixWithSpace(c.cmdLine[0]) {
+		return nil, false
+	}
+	cmd := &Cmd{
+		Type:   TypeSetAttr,
+		Raw:    strings.Join(c.cmdLine, "\n"),
+		LineNo: c.lineNo,
+	}
+	c.Clear()
+	return cmd, true
+}
+
+// getSetAttrHead returns the attribute name and the data type of "-type"
+// of the setAttr c. The values after them are not tokenized.
+func getSetAttrHead(c *Cmd) (string, string) {
+	if c.Token != nil {
+		_, attrName := getAttrNameFromSetAttr(&c.Token)
+		return attrName, getDataTypeFromAttrCmd(&c.Token)
+	}
+	l := NewLexer(c.Raw)
+	l.Next() // skip setAttr
+	attrName, dataType := "", ""
+	afterFlag := false
+	for {
+		t := l.Next()
+		switch {
+		case t.Type == LexEOF || t.Type == LexSemicolon || t.Type == LexIllegal:
+			return attrName, dataType
+		case t.Type == LexWord && isFlagToken(t.Value):
+			switch t.Value {
+			case "-type", "-typ", "-dt", "-dataType":
+				dataType = strings.Trim(l.Next().Value, "\"")
+			default:
+				afterFlag = true
+				continue
+			}
+		case attrName == "" && t.Type == LexString &&
+			3 <= len(t.Value) && t.Value[1] == '.':
+			attrName = t.Value[1 : len(t.Value)-1]
+		case afterFlag:
+		case attrName != "":
+			// The values.
+			return attrName, dataType
+		}
+		afterFlag = false
+	}
+}
+
+// isFlagToken reports whether s is a flag like "-k", not a number like
+// "-1".
+func isFlagToken(s string) bool {
+	return 2 <= len(s) && s[0] == '-' &&
+		('a' <= s[1] && s[1] <= 'z' || 'A' <= s[1] && s[1] <= 'Z')
+}
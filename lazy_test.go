@@ -0,0 +1,140 @@
+package mayaascii
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalLazy(t *testing.T) {
+	refMa, _ := getReferenceEditsTestMa()
+	ma := getPipelineTestMa(50) + refMa
+	eager, err := Unmarshal(strings.NewReader(ma))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err := UnmarshalLazy(strings.NewReader(ma))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shape, _ := lazy.GetNode("pCubeShape0")
+	sa := shape.GetAttr(".vt[0:3]").attrCmd.(*SetAttrCmd)
+	intTester(intTestData{"len(Attr) before access", len(sa.Attr), 0}, t)
+	intTester(intTestData{"len(GetAttrValue())", len(shape.GetAttr(".vt[4:7]").GetAttrValue()), 24}, t)
+	intTester(intTestData{"len(Attr) after access", len(sa.Attr), 12}, t)
+
+	intTester(intTestData{"len(Nodes)", len(lazy.Nodes), len(eager.Nodes)}, t)
+	for name, en := range eager.Nodes {
+		ln, err := lazy.GetNode(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intTester(intTestData{name + " len(Attrs)", len(ln.Attrs), len(en.Attrs)}, t)
+		for i, ea := range en.Attrs {
+			la := ln.Attrs[i]
+			title := name + la.GetName()
+			stringTester(stringTestData{title, la.GetName(), ea.GetName()}, t)
+			stringTester(stringTestData{title + " type",
+				la.GetAttrType().String(), ea.GetAttrType().String()}, t)
+			boolTester(boolTestData{title + " keyable", la.IsKeyable(), ea.IsKeyable()}, t)
+			boolTester(boolTestData{title + " channelBox", la.IsChannelBox(), ea.IsChannelBox()}, t)
+			stringTester(stringTestData{title + " value",
+				fmt.Sprint(la.GetAttrValue()), fmt.Sprint(ea.GetAttrValue())}, t)
+		}
+	}
+	if len(lazy.referenceEditsAttrs()) != len(eager.referenceEditsAttrs()) {
+		t.Errorf("got len(referenceEditsAttrs()) %d, wont %d",
+			len(lazy.referenceEditsAttrs()), len(eager.referenceEditsAttrs()))
+	}
+}
+
+func TestUnmarshalLazy_Error(t *testing.T) {
+	ma := `createNode mesh -n "meshShape";
+	setAttr ".v" no;
+	setAttr -s 2 ".vt[0:1]" -type "float3" 0 0 0 a b c;
+	setAttr ".vt[2]" -type "float3" 1 1 1;
+	setAttr ".io" yes;
+createNode transform -n "pCube1";
+	setAttr ".v" no;`
+	eager, err := Unmarshal(strings.NewReader(ma))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err := UnmarshalLazy(strings.NewReader(ma))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := lazy.GetNode("meshShape")
+	failed := n.GetAttr(".vt[2]")
+	intTester(intTestData{".vt[2] values", len(failed.GetAttrValue()), 0}, t)
+	if _, err := failed.String(); err == nil {
+		t.Errorf("got nil error of .vt[2], wont error")
+	}
+	for _, name := range []string{"meshShape", "pCube1"} {
+		en, _ := eager.GetNode(name)
+		ln, _ := lazy.GetNode(name)
+		intTester(intTestData{name + " len(Attrs)", len(ln.Attrs), len(en.Attrs)}, t)
+		for i, ea := range en.Attrs {
+			la := ln.Attrs[i]
+			stringTester(stringTestData{name + " name", la.GetName(), ea.GetName()}, t)
+			stringTester(stringTestData{name + la.GetName() + " value",
+				fmt.Sprint(la.GetAttrValue()), fmt.Sprint(ea.GetAttrValue())}, t)
+		}
+	}
+	var eb, lb bytes.Buffer
+	if err := eager.Marshal(&eb); err != nil {
+		t.Fatal(err)
+	}
+	if err := lazy.Marshal(&lb); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Marshal", lb.String(), eb.String()}, t)
+}
+
+func TestUnmarshalLazy_Token(t *testing.T) {
+	o, err := UnmarshalLazy(strings.NewReader(`requires -dataType "myData" "myPlugin" "1.0";
+createNode transform -n "pCube1";
+	setAttr -k on ".tx" -1;
+createNode myNode -n "myNode1";
+	setAttr ".md" -type "myData" 1;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := o.GetNode("pCube1")
+	a := n.Attrs[0]
+	stringTester(stringTestData{"name", a.GetName(), ".tx"}, t)
+	intTester(intTestData{"len(Token) before access",
+		len(a.attrCmd.(*SetAttrCmd).Cmd.Token), 0}, t)
+	boolTester(boolTestData{"IsKeyable", a.IsKeyable(), true}, t)
+	intTester(intTestData{"len(Token) after access", len(a.getCmd().Token), 5}, t)
+
+	// The data type binds the node to the requires before access.
+	my, _ := o.GetNode("myNode1")
+	intTester(intTestData{"Data", len(o.Requires[0].Data), 1}, t)
+	boolTester(boolTestData{"Data node", o.Requires[0].Data[0] == my, true}, t)
+
+	for _, c := range []*Cmd{
+		NewCmd(`setAttr -k on ".a" -type "string" "-type";`),
+		{Raw: `setAttr -k on ".a" -type "string" "-type";`},
+	} {
+		attrName, dataType := getSetAttrHead(c)
+		stringTester(stringTestData{"getSetAttrHead name", attrName, ".a"}, t)
+		stringTester(stringTestData{"getSetAttrHead type", dataType, "string"}, t)
+	}
+}
+
+func BenchmarkUnmarshal_Lazy(b *testing.B) {
+	ma := getPipelineTestMa(5000)
+	b.SetBytes(int64(len(ma)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := &Object{Nodes: map[string]*Node{}, connections: NewConnections()}
+		if err := o.unmarshal(context.Background(), strings.NewReader(ma), UnmarshalOptions{Lazy: true}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package mayaascii
+
+import "strings"
+
+// LexTokenType is the type of LexToken.
+type LexTokenType int
+
+const (
+	LexEOF LexTokenType = iota
+	// LexIllegal is an unterminated string or block comment.
+	LexIllegal
+	// LexWord is a command name, a flag, a number or a bare word like
+	// "on" and ":time1".
+	LexWord
+	// LexString is a string with the double quotes.
+	LexString
+	LexLineComment
+	LexBlockComment
+	LexSemicolon
+	LexOpenParen
+	LexCloseParen
+	LexOpenBrace
+	LexCloseBrace
+	LexComma
+)
+
+var lexTokenTypeNames = map[LexTokenType]string{
+	LexEOF:          "EOF",
+	LexIllegal:      "Illegal",
+	LexWord:         "Word",
+	LexString:       "String",
+	LexLineComment:  "LineComment",
+	LexBlockComment: "BlockComment",
+	LexSemicolon:    "Semicolon",
+	LexOpenParen:    "OpenParen",
+	LexCloseParen:   "CloseParen",
+	LexOpenBrace:    "OpenBrace",
+	LexCloseBrace:   "CloseBrace",
+	LexComma:        "Comma",
+}
+
+func (t LexTokenType) String() string {
+	if name, ok := lexTokenTypeNames[t]; ok {
+		return name
+	}
+	return "LexTokenType(?)"
+}
+
+// lexSymbol returns the type of the single byte token c.
+func lexSymbol(c byte) (LexTokenType, bool) {
+	switch c {
+	case ';':
+		return LexSemicolon, true
+	case '(':
+		return LexOpenParen, true
+	case ')':
+		return LexCloseParen, true
+	case '{':
+		return LexOpenBrace, true
+	case '}':
+		return LexCloseBrace, true
+	case ',':
+		return LexComma, true
+	}
+	return LexEOF, false
+}
+
+// LexToken is a token of Maya ASCII source.
+type LexToken struct {
+	Type LexTokenType
+	// Value is the source text of the token, with the double quotes of
+	// a string and the "//" of a comment.
+	Value string
+	// Offset is the byte offset from the head of the source.
+	Offset int
+	// Line and Column are 1-based. Column counts bytes.
+	Line   int
+	Column int
+}
+
+// lexState is the state of Lexer at the end of the source, so that the
+// next line of a command continues a string or a block comment.
+type lexState int
+
+const (
+	lexStateNone lexState = iota
+	lexStateString
+	lexStateStringEscape
+	lexStateBlockComment
+)
+
+// Lexer splits Maya ASCII source into tokens. It is aware of strings,
+// escapes, comments and "\r\n", and never fails: an unterminated string
+// or block comment is returned as LexIllegal.
+type Lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+	state  lexState
+}
+
+func NewLexer(src string) *Lexer {
+	return &Lexer{src: src, line: 1, column: 1}
+}
+
+// Lex returns the tokens of src without LexEOF.
+func Lex(src string) []LexToken {
+	var tokens []LexToken
+	l := NewLexer(src)
+	for {
+		t := l.Next()
+		if t.Type == LexEOF {
+			return tokens
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+// Next returns the next token, or LexEOF at the end of the source.
+func (l *Lexer) Next() LexToken {
+	if l.state == lexStateNone {
+		l.skipSpace()
+	}
+	t := LexToken{Offset: l.pos, Line: l.line, Column: l.column}
+	if len(l.src) <= l.pos {
+		t.Type = LexEOF
+		return t
+	}
+	rest := l.src[l.pos:]
+	var size int
+	switch {
+	case l.state == lexStateString || l.state == lexStateStringEscape:
+		// The string continues from the previous source.
+		t.Type, size = l.scanString(0)
+	case l.state == lexStateBlockComment:
+		t.Type, size = l.scanBlockComment(0)
+	case strings.HasPrefix(rest, "//"):
+		t.Type = LexLineComment
+		size = strings.IndexAny(rest, "\r\n")
+		if size == -1 {
+			size = len(rest)
+		}
+	case strings.HasPrefix(rest, "/*"):
+		t.Type, size = l.scanBlockComment(2)
+	case rest[0] == '"':
+		t.Type, size = l.scanString(1)
+	default:
+		if tt, ok := lexSymbol(rest[0]); ok {
+			t.Type = tt
+			size = 1
+			break
+		}
+		t.Type = LexWord
+		size = l.scanWord()
+	}
+	t.Value = rest[:size]
+	l.advance(size)
+	return t
+}
+
+// scanString returns the size of the string from the current position.
+// The scan starts at start, after the opening quote.
+func (l *Lexer) scanString(start int) (LexTokenType, int) {
+	rest := l.src[l.pos:]
+	escaped := l.state == lexStateStringEscape
+	for i := start; i < len(rest); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case rest[i] == '\\':
+			escaped = true
+		case rest[i] == '"':
+			l.state = lexStateNone
+			return LexString, i + 1
+		}
+	}
+	l.state = lexStateString
+	if escaped {
+		l.state = lexStateStringEscape
+	}
+	return LexIllegal, len(rest)
+}
+
+// scanBlockComment returns the size of the block comment from the current
+// position. The scan starts at start, after "/*".
+func (l *Lexer) scanBlockComment(start int) (LexTokenType, int) {
+	rest := l.src[l.pos:]
+	if i := strings.Index(rest[start:], "*/"); i != -1 {
+		l.state = lexStateNone
+		return LexBlockComment, start + i + 2
+	}
+	l.state = lexStateBlockComment
+	return LexIllegal, len(rest)
+}
+
+// scanWord returns the size of the word from the current position.
+func (l *Lexer) scanWord() int {
+	rest := l.src[l.pos:]
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if isLexSpace(c) || c == '"' {
+			return i
+		}
+		if _, ok := lexSymbol(c); ok {
+			return i
+		}
+		if 0 < i && c == '/' && i+1 < len(rest) && (rest[i+1] == '/' || rest[i+1] == '*') {
+			return i
+		}
+	}
+	return len(rest)
+}
+
+func (l *Lexer) skipSpace() {
+	i := 0
+	for l.pos+i < len(l.src) && isLexSpace(l.src[l.pos+i]) {
+		i++
+	}
+	l.advance(i)
+}
+
+// advance moves the position by size bytes and counts the lines. "\r\n"
+// is a line break, and so is "\r" or "\n" alone.
+func (l *Lexer) advance(size int) {
+	for end := l.pos + size; l.pos < end; {
+		c := l.src[l.pos]
+		l.pos++
+		switch {
+		case c == '\n' && 2 <= l.pos && l.src[l.pos-2] == '\r':
+			// "\r\n" was counted at "\r".
+		case c == '\n' || c == '\r':
+			l.line++
+			l.column = 1
+		default:
+			l.column++
+		}
+	}
+}
+
+func isLexSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
@@ -0,0 +1,23 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzLex(f *testing.F) {
+	f.Add("setAttr \".b\" -type \"string\" \"a;\";\r\n")
+	f.Add("file -r -op \"v=0;\n\" \"a.ma\"; // comment")
+	f.Add("/* a */ {\"x\",\"y\"} (\"l\" + \"m\")")
+	f.Fuzz(func(t *testing.T, src string) {
+		checkLex(t, src)
+
+		cb := &CmdBuilder{}
+		for _, line := range strings.Split(src, "\n") {
+			cb.Append(line)
+			if cb.IsCmdEOF() {
+				cb.Clear()
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package mayaascii
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	src := "// comment\r\nsetAttr \".b\" -type \"string\" \"a \\\"b\\\";\"; /* c\n */\n" +
+		"\t{\"x\",\"y\"} (\"l\" + \"m\");"
+	wonts := []struct {
+		tokenType LexTokenType
+		value     string
+		offset    int
+		line      int
+		column    int
+	}{
+		{LexLineComment, "// comment", 0, 1, 1},
+		{LexWord, "setAttr", 12, 2, 1},
+		{LexString, `".b"`, 20, 2, 9},
+		{LexWord, "-type", 25, 2, 14},
+		{LexString, `"string"`, 31, 2, 20},
+		{LexString, `"a \"b\";"`, 40, 2, 29},
+		{LexSemicolon, ";", 50, 2, 39},
+		{LexBlockComment, "/* c\n */", 52, 2, 41},
+		{LexOpenBrace, "{", 62, 4, 2},
+		{LexString, `"x"`, 63, 4, 3},
+		{LexComma, ",", 66, 4, 6},
+		{LexString, `"y"`, 67, 4, 7},
+		{LexCloseBrace, "}", 70, 4, 10},
+		{LexOpenParen, "(", 72, 4, 12},
+		{LexString, `"l"`, 73, 4, 13},
+		{LexWord, "+", 77, 4, 17},
+		{LexString, `"m"`, 79, 4, 19},
+		{LexCloseParen, ")", 82, 4, 22},
+		{LexSemicolon, ";", 83, 4, 23},
+	}
+	tokens := Lex(src)
+	if len(tokens) != len(wonts) {
+		t.Fatalf("got len(tokens) %d, wont %d. %v", len(tokens), len(wonts), tokens)
+	}
+	for i, w := range wonts {
+		tk := tokens[i]
+		stringTester(stringTestData{w.value + " Type", tk.Type.String(), w.tokenType.String()}, t)
+		stringTester(stringTestData{w.value + " Value", tk.Value, w.value}, t)
+		intTester(intTestData{w.value + " Offset", tk.Offset, w.offset}, t)
+		intTester(intTestData{w.value + " Line", tk.Line, w.line}, t)
+		intTester(intTestData{w.value + " Column", tk.Column, w.column}, t)
+	}
+}
+
+func TestLex_Illegal(t *testing.T) {
+	for _, src := range []string{`setAttr ".b" "abc`, "/* abc", `"abc\`} {
+		tokens := Lex(src)
+		last := tokens[len(tokens)-1]
+		stringTester(stringTestData{src, last.Type.String(), LexIllegal.String()}, t)
+	}
+}
+
+func TestLex_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	chars := []byte(" \t\r\n;\"\\/*(){},+-.abc01")
+	for i := 0; i < 2000; i++ {
+		b := make([]byte, r.Intn(64))
+		for j := range b {
+			b[j] = chars[r.Intn(len(chars))]
+		}
+		checkLex(t, string(b))
+	}
+}
+
+// checkLex checks that the tokens of src cover src in order.
+func checkLex(t *testing.T, src string) {
+	offset := 0
+	for _, tk := range Lex(src) {
+		if tk.Offset < offset || len(src) < tk.Offset+len(tk.Value) ||
+			src[tk.Offset:tk.Offset+len(tk.Value)] != tk.Value {
+			t.Fatalf("got %v, wont the token at %d of %q", tk, offset, src)
+		}
+		if strings.TrimSpace(src[offset:tk.Offset]) != "" {
+			t.Fatalf("got %v, wont the token after %q of %q", tk, src[offset:tk.Offset], src)
+		}
+		offset = tk.Offset + len(tk.Value)
+	}
+	if strings.TrimSpace(src[offset:]) != "" {
+		t.Fatalf("got the rest %q of %q, wont no rest", src[offset:], src)
+	}
+}
@@ -0,0 +1,262 @@
+package mayaascii
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// UnmarshalOptions is the options of UnmarshalWithOptions and
+// UnmarshalContext. The limits protect a service from untrusted input:
+// a limit is checked before the memory for it is allocated. A zero limit
+// is unlimited.
+type UnmarshalOptions struct {
+	// MaxCmdSize is the maximum bytes of a command.
+	MaxCmdSize int
+	// MaxTokens is the maximum tokens of a command.
+	MaxTokens int
+	// MaxArraySize is the maximum of the sizes declared in setAttr, like
+	// "-s 100", the count of Int32Array and the counts of polyFaces.
+	MaxArraySize int
+	// MaxNodes is the maximum createNode commands.
+	MaxNodes int
+	// MaxMemory is the budget in bytes of the estimated memory of the
+	// commands and the values declared by the sizes.
+	MaxMemory int64
+
+	// Workers is the number of goroutines. 0 is GOMAXPROCS.
+	Workers int
+	// Lazy decodes the values of setAttr on first access like
+	// UnmarshalLazy.
+	Lazy bool
+	// Progress is called with the progress of UnmarshalContext on the
+	// goroutine of the caller.
+	Progress func(Progress)
+}
+
+// LimitError is the error of UnmarshalWithOptions when the input exceeds
+// a limit of UnmarshalOptions.
+type LimitError struct {
+	// Limit is the name of the field of UnmarshalOptions like "MaxNodes".
+	Limit  string
+	LineNo uint
+	Value  int64
+	Max    int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("line %d: %s %d exceeds the limit %d",
+		e.LineNo, e.Limit, e.Value, e.Max)
+}
+
+const (
+	// tokenCost is the estimated bytes of a token besides the text.
+	tokenCost = 16
+	// arrayElementCost is the estimated bytes of a value of an array.
+	arrayElementCost = 32
+)
+
+// limiter checks the limits of UnmarshalOptions. A nil limiter has no
+// limits.
+type limiter struct {
+	opts   UnmarshalOptions
+	memory int64
+	nodes  int
+}
+
+func newLimiter(opts UnmarshalOptions) *limiter {
+	if opts.MaxCmdSize == 0 && opts.MaxTokens == 0 &&
+		opts.MaxArraySize == 0 && opts.MaxNodes == 0 && opts.MaxMemory == 0 {
+		return nil
+	}
+	return &limiter{opts: opts}
+}
+
+// newLineReader returns the lineReader that stops at a line longer than
+// MaxCmdSize or MaxMemory.
+func (l *limiter) newLineReader(br *bufio.Reader) *lineReader {
+	r := &lineReader{br: br}
+	if l == nil {
+		return r
+	}
+	if 0 < l.opts.MaxCmdSize {
+		r.maxLine, r.limit = l.opts.MaxCmdSize, "MaxCmdSize"
+	}
+	if 0 < l.opts.MaxMemory && (r.maxLine == 0 || l.opts.MaxMemory < int64(r.maxLine)) {
+		r.maxLine, r.limit = int(l.opts.MaxMemory), "MaxMemory"
+	}
+	return r
+}
+
+// charge adds n bytes to the memory. It is safe for concurrent use.
+func (l *limiter) charge(lineNo uint, n int64) error {
+	if l == nil || l.opts.MaxMemory == 0 {
+		return nil
+	}
+	m := atomic.AddInt64(&l.memory, n)
+	if l.opts.MaxMemory < m {
+		return &LimitError{Limit: "MaxMemory", LineNo: lineNo, Value: m, Max: l.opts.MaxMemory}
+	}
+	return nil
+}
+
+// appendLine appends line to cb after checking the size of the command,
+// and checks the tokens. lineNo is the line number of line.
+func (l *limiter) appendLine(cb *CmdBuilder, line string, lineNo uint) error {
+	if l == nil {
+		cb.Append(line)
+		return nil
+	}
+	size := int64(cb.size + len(line))
+	if !cb.IsClear() {
+		size++
+	}
+	if max := int64(l.opts.MaxCmdSize); 0 < max && max < size {
+		return &LimitError{Limit: "MaxCmdSize", LineNo: lineNo, Value: size, Max: max}
+	}
+	tokens := cb.tokens
+	cb.Append(line)
+	if max := int64(l.opts.MaxTokens); 0 < max && max < int64(cb.tokens) {
+		return &LimitError{Limit: "MaxTokens", LineNo: lineNo, Value: int64(cb.tokens), Max: max}
+	}
+	// The line is held by the lines, Raw and Token of the command.
+	return l.charge(lineNo, int64(3*len(line)+tokenCost*(cb.tokens-tokens)))
+}
+
+// countNode counts the createNode command of cb.
+func (l *limiter) countNode(cb *CmdBuilder) error {
+	if l == nil || l.opts.MaxNodes == 0 || cb.IsClear() ||
+		!TypeCreateNode.HasPrefixWithSpace(cb.cmdLine[0]) {
+		return nil
+	}
+	l.nodes++
+	if l.opts.MaxNodes < l.nodes {
+		return &LimitError{
+			Limit:  "MaxNodes",
+			LineNo: cb.lineNo,
+			Value:  int64(l.nodes),
+			Max:    int64(l.opts.MaxNodes),
+		}
+	}
+	return nil
+}
+
+// checkSetAttr checks the sizes declared in the setAttr command before
+// ParseSetAttr allocates the values.
+func (l *limiter) checkSetAttr(c *Cmd) error {
+	if l == nil || c.Type != TypeSetAttr {
+		return nil
+	}
+	var counts []int64
+	for i := 1; i+1 < len(c.Token); i++ {
+		switch c.Token[i] {
+		case "-s":
+			counts = append(counts, getDeclaredCount(c.Token, i+1))
+		case "-type":
+			counts = append(counts, getHeaderCounts(c.Token, i+2, trimQuote(c.Token[i+1]))...)
+		}
+	}
+	var total int64
+	for _, n := range counts {
+		if max := int64(l.opts.MaxArraySize); 0 < max && max < n {
+			return &LimitError{Limit: "MaxArraySize", LineNo: c.LineNo, Value: n, Max: max}
+		}
+		total += n
+	}
+	return l.charge(c.LineNo, total*arrayElementCost)
+}
+
+// getDeclaredCount returns the count at i, or 0 if it is not a count.
+func getDeclaredCount(token []string, i int) int64 {
+	if i < 0 || len(token) <= i {
+		return 0
+	}
+	n, err := strconv.ParseInt(token[i], 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// getHeaderCounts returns the counts in the values of typeName from start.
+func getHeaderCounts(token []string, start int, typeName string) []int64 {
+	switch typeName {
+	case "Int32Array", "doubleArray", "pointArray", "vectorArray",
+		"stringArray", "componentList":
+		return []int64{getDeclaredCount(token, start)}
+	case "dataPolyComponent":
+		return []int64{getDeclaredCount(token, start+2)}
+	case "lattice":
+		return []int64{getDeclaredCount(token, start+3)}
+	case "nurbsCurve":
+		knotCount := getDeclaredCount(token, start+5)
+		if int64(len(token)) < knotCount {
+			return []int64{knotCount}
+		}
+		return []int64{knotCount, getDeclaredCount(token, start+6+int(knotCount))}
+	case "polyFaces":
+		var counts []int64
+		for i := start; i < len(token); i++ {
+			switch token[i] {
+			case "f", "h", "fc":
+				counts = append(counts, getDeclaredCount(token, i+1))
+			case "mc", "mu":
+				counts = append(counts, getDeclaredCount(token, i+2))
+			}
+		}
+		return counts
+	}
+	return nil
+}
+
+// lineReader reads lines without the line breaks.
+type lineReader struct {
+	br *bufio.Reader
+	// maxLine is the maximum bytes of a line. 0 is unlimited.
+	maxLine int
+	// limit is the name of the limit of maxLine.
+	limit  string
+	lineNo uint
+}
+
+// readLine reads a line. A line longer than maxLine is a LimitError
+// before the whole line is read into the memory.
+func (r *lineReader) readLine() (string, error) {
+	buf, isPrefix, err := r.br.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	r.lineNo++
+	if err := r.checkLine(len(buf)); err != nil {
+		return "", err
+	}
+	if !isPrefix {
+		return string(buf), nil
+	}
+	bb := bytes.NewBuffer(append([]byte{}, buf...))
+	for isPrefix {
+		buf, isPrefix, err = r.br.ReadLine()
+		if err != nil {
+			break
+		}
+		if err := r.checkLine(bb.Len() + len(buf)); err != nil {
+			return "", err
+		}
+		bb.Write(buf)
+	}
+	return bb.String(), nil
+}
+
+func (r *lineReader) checkLine(size int) error {
+	if 0 < r.maxLine && r.maxLine < size {
+		return &LimitError{
+			Limit:  r.limit,
+			LineNo: r.lineNo,
+			Value:  int64(size),
+			Max:    int64(r.maxLine),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalWithOptions(t *testing.T) {
+	ma := getPipelineTestMa(10)
+	opts := UnmarshalOptions{
+		MaxCmdSize:   1024,
+		MaxTokens:    256,
+		MaxArraySize: 100,
+		MaxNodes:     20,
+		MaxMemory:    1 << 20,
+	}
+	o, err := UnmarshalWithOptions(strings.NewReader(ma), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"Nodes", len(o.Nodes), 20}, t)
+}
+
+func TestUnmarshalWithOptions_Limit(t *testing.T) {
+	for _, d := range []struct {
+		title  string
+		ma     string
+		opts   UnmarshalOptions
+		limit  string
+		lineNo uint
+	}{
+		{
+			title: "-s",
+			ma: "createNode mesh -n \"m\";\n" +
+				"\tsetAttr -s 999999999 \".vt[0:1]\" 0 0 0 1 1 1;\n",
+			opts:   UnmarshalOptions{MaxArraySize: 1000},
+			limit:  "MaxArraySize",
+			lineNo: 2,
+		},
+		{
+			title: "Int32Array",
+			ma: "createNode polySplit -n \"s\";\n" +
+				"\tsetAttr \".sps[0].sp\" -type \"Int32Array\" 999999999 1 2;\n",
+			opts:   UnmarshalOptions{MaxArraySize: 1000},
+			limit:  "MaxArraySize",
+			lineNo: 2,
+		},
+		{
+			title: "polyFaces",
+			ma: "createNode mesh -n \"m\";\n" +
+				"\tsetAttr \".fc[0]\" -type \"polyFaces\" \n" +
+				"\t\tf 3 0 1 2\n" +
+				"\t\tmu 0 999999999 0 1 2;\n",
+			opts:   UnmarshalOptions{MaxArraySize: 1000},
+			limit:  "MaxArraySize",
+			lineNo: 4,
+		},
+		{
+			title:  "memory of -s",
+			ma:     "select -ne :time1;\n\tsetAttr -s 999999999 \".a\";\n",
+			opts:   UnmarshalOptions{MaxMemory: 1 << 20},
+			limit:  "MaxMemory",
+			lineNo: 2,
+		},
+		{
+			title:  "command size",
+			ma:     "createNode transform\n -n \"" + strings.Repeat("a", 100) + "\";\n",
+			opts:   UnmarshalOptions{MaxCmdSize: 64},
+			limit:  "MaxCmdSize",
+			lineNo: 2,
+		},
+		{
+			title:  "line size",
+			ma:     "createNode transform -n \"" + strings.Repeat("a", 8192) + "\";\n",
+			opts:   UnmarshalOptions{MaxMemory: 4096},
+			limit:  "MaxMemory",
+			lineNo: 1,
+		},
+		{
+			title:  "tokens",
+			ma:     "select -ne :time1;\n\tsetAttr \".a\" " + strings.Repeat("1 ", 100) + ";\n",
+			opts:   UnmarshalOptions{MaxTokens: 50},
+			limit:  "MaxTokens",
+			lineNo: 2,
+		},
+		{
+			title:  "nodes",
+			ma:     getPipelineTestMa(10),
+			opts:   UnmarshalOptions{MaxNodes: 5},
+			limit:  "MaxNodes",
+			lineNo: 2 + 2*14 + 3,
+		},
+	} {
+		_, err := UnmarshalWithOptions(strings.NewReader(d.ma), d.opts)
+		le, ok := err.(*LimitError)
+		if !ok {
+			t.Errorf("%s: got %v, wont *LimitError", d.title, err)
+			continue
+		}
+		stringTester(stringTestData{d.title + " Limit", le.Limit, d.limit}, t)
+		intTester(intTestData{d.title + " LineNo", int(le.LineNo), int(d.lineNo)}, t)
+	}
+}
+
+func TestUnmarshalWithOptions_Unlimited(t *testing.T) {
+	ma := "createNode mesh -n \"m\";\n" +
+		"\tsetAttr -s 999999999 \".vt[0:1]\" 0 0 0 1 1 1;\n"
+	o, err := UnmarshalWithOptions(strings.NewReader(ma), UnmarshalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := o.GetNode("m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{".vt values", len(m.GetAttr(".vt[0:1]").GetAttrValue()), 6}, t)
+}
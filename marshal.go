@@ -0,0 +1,111 @@
+package mayaascii
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/transform"
+)
+
+// GetCodeset returns the codeset of the file opened by Open, like "932".
+// It is "" for a file without the "//Codeset:" header and for Unmarshal.
+func (o *Object) GetCodeset() string {
+	return o.codeset
+}
+
+// Marshal writes the commands of o to w as a Maya ASCII file. The deleted
+// nodes and attributes are not written. The file opened by Open is
+// written in its codeset.
+func (o *Object) Marshal(w io.Writer) error {
+	if e := getCodesetEncoding(o.codeset); e != nil {
+		tw := transform.NewWriter(w, e.NewEncoder())
+		if err := o.marshal(tw); err != nil {
+			return err
+		}
+		return tw.Close()
+	}
+	return o.marshal(w)
+}
+
+func (o *Object) marshal(w io.Writer) error {
+	deleted := o.deletedCmds()
+	bw := bufio.NewWriter(w)
+	for _, c := range o.cmds {
+		if _, ok := deleted[c]; ok {
+			continue
+		}
+		if _, err := bw.WriteString(c.Raw); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// deletedCmds returns the commands of the deleted nodes and attributes.
+func (o *Object) deletedCmds() map[*Cmd]struct{} {
+	deleted := map[*Cmd]struct{}{}
+	for _, n := range o.Nodes {
+		if n.isDeleted {
+			deleted[n.createNodeCmd.Cmd] = struct{}{}
+			if n.renameCmd != nil {
+				deleted[n.renameCmd.Cmd] = struct{}{}
+			}
+		}
+		for _, a := range n.Attrs {
+			if a.isDeleted {
+				deleted[a.getCmd()] = struct{}{}
+			}
+		}
+	}
+	return deleted
+}
+
+// Save writes o to the file at path with Marshal. The header is refreshed
+// with the file name of path and the current time. The file is compressed
+// if path ends with ".gz" or ".zst".
+func (o *Object) Save(path string) error {
+	h := o.Header()
+	h.FileName = filepath.Base(path)
+	for _, ext := range []string{".gz", ".zst"} {
+		h.FileName = strings.TrimSuffix(h.FileName, ext)
+	}
+	h.LastModified = time.Now()
+	o.SetHeader(h)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var w io.WriteCloser
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		w = gzip.NewWriter(f)
+	case strings.HasSuffix(path, ".zst"):
+		if w, err = zstd.NewWriter(f); err != nil {
+			return err
+		}
+	}
+	if w == nil {
+		if err := o.Marshal(f); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	if err := o.Marshal(w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
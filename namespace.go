@@ -0,0 +1,333 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// namespaceRegexp matches "ns" and "parent:ns".
+var namespaceRegexp = regexp.MustCompile(
+	`^([A-Za-z_][A-Za-z0-9_]*:)*[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Namespace is a node of the tree returned by Object.Namespaces.
+type Namespace struct {
+	Name     string
+	Parent   *Namespace
+	Children []*Namespace
+	Nodes    []*Node
+}
+
+// GetFullName returns the namespace with its parents like "parent:ns".
+// The root namespace is "".
+func (ns *Namespace) GetFullName() string {
+	if ns.Parent == nil || ns.Parent.Name == "" {
+		return ns.Name
+	}
+	return ns.Parent.GetFullName() + ":" + ns.Name
+}
+
+func (ns *Namespace) child(name string) *Namespace {
+	for _, c := range ns.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	c := &Namespace{
+		Name:     name,
+		Parent:   ns,
+		Children: []*Namespace{},
+		Nodes:    []*Node{},
+	}
+	ns.Children = append(ns.Children, c)
+	return c
+}
+
+func (ns *Namespace) sort() {
+	sort.Slice(ns.Children, func(i, j int) bool {
+		return ns.Children[i].Name < ns.Children[j].Name
+	})
+	for _, c := range ns.Children {
+		c.sort()
+	}
+}
+
+// splitNamespace splits "ns:name" into "ns" and "name".
+func splitNamespace(name string) (string, string) {
+	name = strings.TrimPrefix(name, ":")
+	i := strings.LastIndex(name, ":")
+	if i == -1 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
+// getFullNamespace returns the namespace of the reference with the
+// namespaces of its parent references.
+func (f *File) getFullNamespace() string {
+	if f.Parent != nil && f.GetReferenceDepthInfo() > 1 {
+		parent := f.Parent.getFullNamespace()
+		if parent != "" {
+			return parent + ":" + f.GetNamespace()
+		}
+	}
+	return f.GetNamespace()
+}
+
+// Namespaces returns the root namespace of the scene. The tree is made of
+// the namespaces of the nodes, the references and the namespaces added
+// by AddNamespace.
+func (o *Object) Namespaces() *Namespace {
+	root := &Namespace{
+		Children: []*Namespace{},
+		Nodes:    []*Node{},
+	}
+	find := func(fullName string) *Namespace {
+		ns := root
+		if fullName == "" {
+			return ns
+		}
+		for _, name := range strings.Split(fullName, ":") {
+			ns = ns.child(name)
+		}
+		return ns
+	}
+	for name := range o.namespaces {
+		find(name)
+	}
+	for _, f := range o.Files {
+		find(f.getFullNamespace())
+	}
+	for _, n := range o.sortedNodes() {
+		ns, _ := splitNamespace(n.GetName())
+		node := find(ns)
+		node.Nodes = append(node.Nodes, n)
+	}
+	root.sort()
+	return root
+}
+
+// hasNamespace reports whether the namespace exists in the scene.
+func (o *Object) hasNamespace(fullName string) bool {
+	if _, ok := o.namespaces[fullName]; ok {
+		return true
+	}
+	for _, f := range o.Files {
+		if isInNamespace(f.getFullNamespace()+":", fullName) {
+			return true
+		}
+	}
+	for name := range o.Nodes {
+		if isInNamespace(name, fullName) {
+			return true
+		}
+	}
+	for name := range o.namespaces {
+		if isInNamespace(name+":", fullName) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInNamespace reports whether name is in the namespace ns or in its
+// children.
+func isInNamespace(name, ns string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(name, ":"), ns+":")
+}
+
+// AddNamespace adds an empty namespace like "ns" or "parent:ns".
+func (o *Object) AddNamespace(ns string) error {
+	ns = strings.TrimPrefix(ns, ":")
+	if !namespaceRegexp.MatchString(ns) {
+		return errors.New(fmt.Sprintf("\"%s\" is invalid namespace", ns))
+	}
+	if o.hasNamespace(ns) {
+		return errors.New(fmt.Sprintf("%s namespace already exists", ns))
+	}
+	if o.namespaces == nil {
+		o.namespaces = map[string]struct{}{}
+	}
+	o.namespaces[ns] = struct{}{}
+	return nil
+}
+
+// RenameNamespace renames the namespace old and moves its nodes,
+// connections, references and reference edits to the namespace new.
+func (o *Object) RenameNamespace(old, new string) error {
+	old = strings.TrimPrefix(old, ":")
+	new = strings.TrimPrefix(new, ":")
+	if !o.hasNamespace(old) {
+		return errors.New(fmt.Sprintf("%s namespace was not found", old))
+	}
+	if !namespaceRegexp.MatchString(new) {
+		return errors.New(fmt.Sprintf("\"%s\" is invalid namespace", new))
+	}
+	if o.hasNamespace(new) {
+		return errors.New(fmt.Sprintf("%s namespace already exists", new))
+	}
+	if isInNamespace(new, old) {
+		return errors.New(fmt.Sprintf("%s can not be moved into itself", old))
+	}
+	if err := o.renameReferenceNamespaces(old, new); err != nil {
+		return err
+	}
+	o.moveNamespace(old, new, map[string]string{})
+	return nil
+}
+
+// RemoveNamespace removes the namespace ns. An empty namespace is removed
+// as it is. With mergeWithParent, the nodes of ns are moved to its parent
+// namespace and renamed like Maya does when their names conflict.
+// The namespace of a reference can't be removed.
+func (o *Object) RemoveNamespace(ns string, mergeWithParent bool) error {
+	ns = strings.TrimPrefix(ns, ":")
+	if ns == "" {
+		return errors.New("root namespace can not be removed")
+	}
+	if !o.hasNamespace(ns) {
+		return errors.New(fmt.Sprintf("%s namespace was not found", ns))
+	}
+	for _, f := range o.Files {
+		fns := f.getFullNamespace()
+		if fns == ns || isInNamespace(fns, ns) {
+			return errors.New(fmt.Sprintf(
+				"%s namespace belongs to the reference %s", ns, f.GetReferenceNode()))
+		}
+	}
+
+	var nodes []*Node
+	for _, n := range o.sortedNodes() {
+		if isInNamespace(n.GetName(), ns) {
+			nodes = append(nodes, n)
+		}
+	}
+	if !mergeWithParent {
+		isEmpty := len(nodes) == 0
+		for name := range o.namespaces {
+			if isInNamespace(name, ns) {
+				isEmpty = false
+			}
+		}
+		if !isEmpty {
+			return errors.New(fmt.Sprintf("%s namespace is not empty", ns))
+		}
+		delete(o.namespaces, ns)
+		return nil
+	}
+
+	parent, _ := splitNamespace(ns)
+	moved := map[string]string{}
+	used := map[string]struct{}{}
+	isUsed := func(name string) bool {
+		_, inNodes := o.Nodes[name]
+		_, inUsed := used[name]
+		return inNodes || inUsed
+	}
+	for _, n := range nodes {
+		name := uniqueName(moveName(n.GetName(), ns, parent), isUsed)
+		used[name] = struct{}{}
+		moved[n.GetName()] = name
+	}
+	o.moveNamespace(ns, parent, moved)
+	return nil
+}
+
+// moveNamespace moves every name in the namespace old to new.
+// renamed overrides the new names of the nodes.
+func (o *Object) moveNamespace(old, new string, renamed map[string]string) {
+	o.rewriteNodeRefs(func(path string) (string, bool) {
+		comps := strings.Split(path, "|")
+		changed := false
+		for i, comp := range comps {
+			name := strings.TrimPrefix(comp, ":")
+			mapped, ok := renamed[name]
+			if !ok {
+				if !isInNamespace(name, old) {
+					continue
+				}
+				mapped = moveName(name, old, new)
+			}
+			if strings.HasPrefix(comp, ":") {
+				mapped = ":" + mapped
+			}
+			comps[i] = mapped
+			changed = true
+		}
+		return strings.Join(comps, "|"), changed
+	})
+
+	for _, n := range o.sortedNodes() {
+		name, ok := renamed[n.GetName()]
+		if !ok {
+			if !isInNamespace(n.GetName(), old) {
+				continue
+			}
+			name = moveName(n.GetName(), old, new)
+		}
+		o.setNodeName(n, name)
+	}
+
+	var movedNamespaces []string
+	for name := range o.namespaces {
+		if name == old || isInNamespace(name, old) {
+			movedNamespaces = append(movedNamespaces, name)
+		}
+	}
+	for _, name := range movedNamespaces {
+		delete(o.namespaces, name)
+		if name = strings.TrimPrefix(name, old); new != "" {
+			o.namespaces[new+name] = struct{}{}
+		} else if name != "" {
+			o.namespaces[strings.TrimPrefix(name, ":")] = struct{}{}
+		}
+	}
+}
+
+// renameReferenceNamespaces rewrites the -ns flag of the references in
+// the namespace old.
+func (o *Object) renameReferenceNamespaces(old, new string) error {
+	type nsEdit struct {
+		f  *File
+		ns string
+	}
+	var edits []nsEdit
+	for _, f := range o.Files {
+		fns := f.getFullNamespace()
+		if fns != old && !isInNamespace(fns, old) {
+			continue
+		}
+		fns = new + strings.TrimPrefix(fns, old)
+		if f.Parent != nil && f.GetReferenceDepthInfo() > 1 {
+			parent := f.Parent.getFullNamespace()
+			if parent == old || isInNamespace(parent, old) {
+				// the -ns flag is relative to the parent reference.
+				continue
+			}
+			if !isInNamespace(fns, parent) {
+				return errors.New(fmt.Sprintf(
+					"%s can not be moved out of the parent reference %s",
+					f.GetNamespace(), f.Parent.GetReferenceNode()))
+			}
+			fns = strings.TrimPrefix(fns, parent+":")
+		}
+		edits = append(edits, nsEdit{f: f, ns: fns})
+	}
+	for _, e := range edits {
+		e.f.fileCmd.Cmd.replaceToken(quote(e.f.fileCmd.Namespace), quote(e.ns))
+		e.f.fileCmd.Namespace = e.ns
+	}
+	return nil
+}
+
+// moveName moves name in the namespace old to the namespace new.
+func moveName(name, old, new string) string {
+	name = strings.TrimPrefix(name, ":")
+	rest := strings.TrimPrefix(name, old+":")
+	if new == "" {
+		return rest
+	}
+	return new + ":" + rest
+}
@@ -0,0 +1,194 @@
+package mayaascii
+
+// MayaNodeTypes is the set of node types that Maya provides without any
+// plugin, used by AnalyzeRequires to find the node types of unloaded or
+// undeclared plugins. The set is not complete for every Maya version, so
+// studios can add their built-in types to it, or pass their own set to
+// AnalyzeRequiresWithNodeTypes.
+var MayaNodeTypes = map[string]struct{}{
+	"addDoubleLinear": {}, "addMatrix": {}, "aimConstraint": {},
+	"aimMatrix": {}, "airField": {}, "alignCurve": {}, "alignSurface": {},
+	"ambientLight": {}, "angleBetween": {}, "angleDimension": {},
+	"animBlendInOut": {}, "animBlendNodeAdditive": {},
+	"animBlendNodeAdditiveDA": {}, "animBlendNodeAdditiveDL": {},
+	"animBlendNodeAdditiveF": {}, "animBlendNodeAdditiveFA": {},
+	"animBlendNodeAdditiveFL": {}, "animBlendNodeAdditiveI16": {},
+	"animBlendNodeAdditiveI32": {}, "animBlendNodeAdditiveRotation": {},
+	"animBlendNodeAdditiveScale": {}, "animBlendNodeBoolean": {},
+	"animBlendNodeEnum": {}, "animBlendNodeTime": {}, "animClip": {},
+	"animCurveTA": {}, "animCurveTL": {}, "animCurveTT": {},
+	"animCurveTU": {}, "animCurveUA": {}, "animCurveUL": {},
+	"animCurveUT": {}, "animCurveUU": {}, "animLayer": {}, "anisotropic": {},
+	"annotationShape": {}, "arcLengthDimension": {}, "areaLight": {},
+	"arrayMapper": {}, "attachCurve": {}, "attachSurface": {}, "audio": {},
+	"avgCurves": {}, "avgNurbsSurfacePoints": {}, "avgSurfacePoints": {},
+	"baseLattice": {}, "bevel": {}, "bevelPlus": {}, "birailSrf": {},
+	"blendColors": {}, "blendMatrix": {}, "blendShape": {},
+	"blendTwoAttr": {}, "blendWeighted": {}, "blindDataTemplate": {},
+	"blinn": {}, "boneLattice": {}, "boolean": {}, "boundary": {},
+	"brownian": {}, "brush": {}, "bulge": {}, "bump2d": {}, "bump3d": {},
+	"cacheBlend": {}, "cacheFile": {}, "camera": {}, "cameraSet": {},
+	"cameraView": {}, "character": {}, "characterMap": {},
+	"characterOffset": {}, "checker": {}, "choice": {}, "chooser": {},
+	"clamp": {}, "clipGhostShape": {}, "clipLibrary": {}, "clipScheduler": {},
+	"closeCurve": {}, "closestPointOnMesh": {}, "closestPointOnSurface": {},
+	"closeSurface": {}, "cloth": {}, "cloud": {}, "cluster": {},
+	"clusterFlexorShape": {}, "clusterHandle": {},
+	"colorManagementGlobals": {}, "colorProfile": {}, "combinationShape": {},
+	"composeMatrix": {}, "condition": {}, "container": {}, "contrast": {},
+	"controller": {}, "copyColorSet": {}, "copyUVSet": {}, "crater": {},
+	"creaseSet": {}, "createColorSet": {}, "curveFromMeshCoM": {},
+	"curveFromMeshEdge": {}, "curveFromSubdivEdge": {},
+	"curveFromSubdivFace": {}, "curveFromSurfaceBnd": {},
+	"curveFromSurfaceCoS": {}, "curveFromSurfaceIso": {}, "curveInfo": {},
+	"curveIntersect": {}, "curveNormalizerAngle": {},
+	"curveNormalizerLinear": {}, "curveRange": {}, "curveVarGroup": {},
+	"curveWarp": {}, "dagContainer": {}, "dagPose": {}, "decomposeMatrix": {},
+	"defaultLightList": {}, "defaultRenderingList": {},
+	"defaultRenderUtilityList": {}, "defaultShaderList": {},
+	"defaultTextureList": {}, "deformBend": {}, "deformFlare": {},
+	"deformSine": {}, "deformSquash": {}, "deformTwist": {}, "deformWave": {},
+	"deleteColorSet": {}, "deleteComponent": {}, "deleteUVSet": {},
+	"deltaMush": {}, "detachCurve": {}, "detachSurface": {},
+	"directionalLight": {}, "displacementShader": {}, "displayLayer": {},
+	"displayLayerManager": {}, "distanceBetween": {}, "distanceDimShape": {},
+	"dof": {}, "doubleShadingSwitch": {}, "dragField": {},
+	"dropoffLocator": {}, "dynamicConstraint": {}, "dynController": {},
+	"dynGlobals": {}, "envBall": {}, "envChrome": {}, "envCube": {},
+	"envFog": {}, "envSky": {}, "envSphere": {}, "explodeNurbsShell": {},
+	"expression": {}, "extendCurve": {}, "extendSurface": {}, "extrude": {},
+	"ffBlendSrf": {}, "ffd": {}, "ffFilletSrf": {}, "file": {},
+	"filletCurve": {}, "fitBspline": {}, "flexorShape": {}, "flow": {},
+	"fluidEmitter": {}, "fluidShape": {}, "fluidTexture2D": {},
+	"fluidTexture3D": {}, "follicle": {}, "fosterParent": {},
+	"fourByFourMatrix": {}, "fractal": {}, "frameCache": {},
+	"gammaCorrect": {}, "geoConnector": {}, "geomBind": {},
+	"geometryConstraint": {}, "geometryFilter": {}, "geometryVarGroup": {},
+	"globalCacheControl": {}, "globalStitch": {}, "granite": {},
+	"gravityField": {}, "greasePencilSequence": {}, "greasePlane": {},
+	"greasePlaneRenderShape": {}, "grid": {}, "groupId": {}, "groupParts": {},
+	"guide": {}, "hairConstraint": {}, "hairSystem": {}, "hairTubeShader": {},
+	"hardenPoint": {}, "hardwareRenderGlobals": {},
+	"hardwareRenderingGlobals": {}, "heightField": {}, "hikSolver": {},
+	"holdMatrix": {}, "hsvToRgb": {}, "hwReflectionMap": {},
+	"hwRenderGlobals": {}, "hyperGraphInfo": {}, "hyperLayout": {},
+	"hyperView": {}, "ikEffector": {}, "ikHandle": {}, "ikMCsolver": {},
+	"ikPASolver": {}, "ikRPsolver": {}, "ikSCsolver": {},
+	"ikSplineSolver": {}, "ikSystem": {}, "imagePlane": {}, "implicitBox": {},
+	"implicitCone": {}, "implicitSphere": {}, "insertKnotCurve": {},
+	"insertKnotSurface": {}, "instancer": {}, "intersectSurface": {},
+	"inverseMatrix": {}, "jiggle": {}, "joint": {}, "jointCluster": {},
+	"jointFfd": {}, "jointLattice": {}, "keyingGroup": {}, "lambert": {},
+	"lattice": {}, "layeredShader": {}, "layeredTexture": {}, "leather": {},
+	"lightFog": {}, "lightInfo": {}, "lightLinker": {}, "lightList": {},
+	"locator": {}, "lodGroup": {}, "lodThresholds": {}, "loft": {},
+	"lookAt": {}, "luminance": {}, "makeCircularArc": {}, "makeGroup": {},
+	"makeIllustratorCurves": {}, "makeNurbCircle": {}, "makeNurbCone": {},
+	"makeNurbCube": {}, "makeNurbCylinder": {}, "makeNurbPlane": {},
+	"makeNurbSphere": {}, "makeNurbsSquare": {}, "makeNurbTorus": {},
+	"makeTextCurves": {}, "makeThreePointCircularArc": {},
+	"makeTwoPointCircularArc": {}, "mandelbrot": {}, "mandelbrot3D": {},
+	"marble": {}, "materialInfo": {}, "membrane": {}, "mesh": {}, "morph": {},
+	"motionPath": {}, "motionTrail": {}, "motionTrailShape": {},
+	"mountain": {}, "movie": {}, "multDoubleLinear": {}, "multiplyDivide": {},
+	"multMatrix": {}, "mute": {}, "nCloth": {}, "nComponent": {},
+	"nearestPointOnCurve": {}, "network": {}, "newtonField": {},
+	"nodeGraphEditorBookmarkInfo": {}, "nodeGraphEditorBookmarks": {},
+	"nodeGraphEditorInfo": {}, "noise": {}, "nonLinear": {},
+	"normalConstraint": {}, "nParticle": {}, "nRigid": {}, "nucleus": {},
+	"nurbsCurve": {}, "nurbsCurveToBezier": {}, "nurbsSurface": {},
+	"nurbsTessellate": {}, "nurbsToSubdiv": {}, "objectAttrFilter": {},
+	"objectBinFilter": {}, "objectFilter": {}, "objectMultiFilter": {},
+	"objectNameFilter": {}, "objectRenderFilter": {},
+	"objectScriptFilter": {}, "objectSet": {}, "objectTypeFilter": {},
+	"ocean": {}, "oceanShader": {}, "offsetCos": {}, "offsetCurve": {},
+	"offsetSurface": {}, "opticalFX": {}, "orientationMarker": {},
+	"orientConstraint": {}, "pairBlend": {}, "parentConstraint": {},
+	"particle": {}, "particleAgeMapper": {}, "particleCloud": {},
+	"particleColorMapper": {}, "particleIncandMapper": {},
+	"particleSamplerInfo": {}, "particleTranspMapper": {}, "partition": {},
+	"passContributionMap": {}, "passMatrix": {}, "pfxGeometry": {},
+	"pfxHair": {}, "pfxToon": {}, "phong": {}, "phongE": {}, "pickMatrix": {},
+	"place2dTexture": {}, "place3dTexture": {}, "planarTrimSurface": {},
+	"plusMinusAverage": {}, "pointConstraint": {}, "pointEmitter": {},
+	"pointLight": {}, "pointMatrixMult": {}, "pointOnCurveInfo": {},
+	"pointOnPolyConstraint": {}, "pointOnSurfaceInfo": {},
+	"poleVectorConstraint": {}, "polyAppend": {}, "polyAppendVertex": {},
+	"polyAutoProj": {}, "polyAverageVertex": {}, "polyBevel": {},
+	"polyBevel2": {}, "polyBevel3": {}, "polyBlindData": {}, "polyBoolOp": {},
+	"polyBridgeEdge": {}, "polyCBoolOp": {}, "polyChipOff": {},
+	"polyCircularize": {}, "polyClean": {}, "polyCloseBorder": {},
+	"polyCollapseEdge": {}, "polyCollapseF": {}, "polyColorDel": {},
+	"polyColorMod": {}, "polyColorPerVertex": {}, "polyCone": {},
+	"polyConnectComponents": {}, "polyContourProj": {}, "polyCopyUV": {},
+	"polyCrease": {}, "polyCreaseEdge": {}, "polyCreateFace": {},
+	"polyCube": {}, "polyCut": {}, "polyCylinder": {}, "polyCylProj": {},
+	"polyDelEdge": {}, "polyDelFacet": {}, "polyDelVertex": {},
+	"polyDisc": {}, "polyDuplicateEdge": {}, "polyEdgeToCurve": {},
+	"polyEditEdgeFlow": {}, "polyExtrudeEdge": {}, "polyExtrudeFace": {},
+	"polyExtrudeVertex": {}, "polyFlipEdge": {}, "polyFlipUV": {},
+	"polyHelix": {}, "polyHoleFace": {}, "polyLayoutUV": {}, "polyMapCut": {},
+	"polyMapDel": {}, "polyMapSew": {}, "polyMapSewMove": {},
+	"polyMergeEdge": {}, "polyMergeFace": {}, "polyMergeUV": {},
+	"polyMergeVert": {}, "polyMirror": {}, "polyMoveEdge": {},
+	"polyMoveFace": {}, "polyMoveFacetUV": {}, "polyMoveUV": {},
+	"polyMoveVertex": {}, "polyNormal": {}, "polyNormalizeUV": {},
+	"polyNormalPerVertex": {}, "polyOptUvs": {}, "polyPinUV": {},
+	"polyPipe": {}, "polyPlanarProj": {}, "polyPlane": {}, "polyPlatonic": {},
+	"polyPlatonicSolid": {}, "polyPoke": {}, "polyPrism": {}, "polyProj": {},
+	"polyPyramid": {}, "polyQuad": {}, "polyReduce": {}, "polyRemesh": {},
+	"polyRetopo": {}, "polySeparate": {}, "polySewEdge": {}, "polySmooth": {},
+	"polySmoothFace": {}, "polySmoothProxy": {}, "polySoftEdge": {},
+	"polySphere": {}, "polySphProj": {}, "polySpinEdge": {}, "polySplit": {},
+	"polySplitEdge": {}, "polySplitRing": {}, "polySplitVert": {},
+	"polyStraightenUVBorder": {}, "polySubdEdge": {}, "polySubdFace": {},
+	"polySuperShape": {}, "polyTorus": {}, "polyToSubdiv": {},
+	"polyTransfer": {}, "polyTriangulate": {}, "polyTweak": {},
+	"polyTweakUV": {}, "polyUnite": {}, "polyUVRectangle": {},
+	"polyWedgeFace": {}, "poseInterpolatorManager": {}, "positionMarker": {},
+	"postProcessList": {}, "projectCurve": {}, "projection": {},
+	"projectTangent": {}, "proximityPin": {}, "proximityWrap": {},
+	"psdFileTex": {}, "quadShadingSwitch": {}, "radialField": {}, "ramp": {},
+	"rampShader": {}, "rebuildCurve": {}, "rebuildSurface": {},
+	"reference": {}, "remapColor": {}, "remapHsv": {}, "remapValue": {},
+	"renderBox": {}, "renderCone": {}, "renderGlobals": {},
+	"renderGlobalsList": {}, "renderLayer": {}, "renderLayerManager": {},
+	"renderPartition": {}, "renderPass": {}, "renderPassSet": {},
+	"renderQuality": {}, "renderRect": {}, "renderSetup": {},
+	"renderSetupLayer": {}, "renderSphere": {}, "renderTarget": {},
+	"reorderUVSet": {}, "resolution": {}, "resultCurveTimeToAngular": {},
+	"resultCurveTimeToLinear": {}, "resultCurveTimeToTime": {},
+	"resultCurveTimeToUnitless": {}, "reverse": {}, "reverseCurve": {},
+	"reverseSurface": {}, "revolve": {}, "rgbToHsv": {}, "rigidBody": {},
+	"rigidConstraint": {}, "rigidSolver": {}, "rock": {},
+	"roundConstantRadius": {}, "samplerInfo": {}, "scaleConstraint": {},
+	"script": {}, "sculpt": {}, "sequenceManager": {}, "sequencer": {},
+	"setRange": {}, "shadingEngine": {}, "shadingMap": {},
+	"shapeEditorManager": {}, "shot": {}, "shrinkWrap": {},
+	"simpleVolumeShader": {}, "singleShadingSwitch": {}, "sketchPlane": {},
+	"skinBinding": {}, "skinCluster": {}, "smoothCurve": {},
+	"smoothTangentSrf": {}, "snapshot": {}, "snapshotShape": {}, "snow": {},
+	"softMod": {}, "softModHandle": {}, "solidFractal": {}, "solidify": {},
+	"spotLight": {}, "spring": {}, "squareSrf": {}, "standardSurface": {},
+	"stencil": {}, "stitchAsNurbsShell": {}, "stitchSrf": {}, "stroke": {},
+	"strokeGlobals": {}, "stucco": {}, "subCurve": {}, "subdiv": {},
+	"subdivToNurbs": {}, "subdivToPoly": {}, "subSurface": {},
+	"surfaceInfo": {}, "surfaceLuminance": {}, "surfaceShader": {},
+	"surfaceVarGroup": {}, "symmetryConstraint": {}, "tangentConstraint": {},
+	"tension": {}, "textureBakeSet": {}, "textureDeformer": {},
+	"textureToGeom": {}, "time": {}, "timeEditor": {},
+	"timeEditorAnimSource": {}, "timeEditorClip": {},
+	"timeEditorClipEvaluator": {}, "timeEditorInterpolator": {},
+	"timeEditorTracks": {}, "timeFunction": {}, "timeToUnitConversion": {},
+	"timeWarp": {}, "toonLineAttributes": {}, "transferAttributes": {},
+	"transform": {}, "transformGeometry": {}, "trim": {},
+	"trimWithBoundaries": {}, "tripleShadingSwitch": {},
+	"turbulenceField": {}, "tweak": {}, "uniformField": {},
+	"unitConversion": {}, "unitToTimeConversion": {}, "unknown": {},
+	"unknownDag": {}, "unknownTransform": {}, "untrim": {},
+	"useBackground": {}, "uvChooser": {}, "uvPin": {}, "vectorProduct": {},
+	"vertexBakeSet": {}, "viewColorManager": {}, "volumeAxisField": {},
+	"volumeFog": {}, "volumeLight": {}, "volumeNoise": {}, "volumeShader": {},
+	"vortexField": {}, "water": {}, "wire": {}, "wood": {}, "wrap": {},
+	"wtAddMatrix": {},
+}
@@ -2,9 +2,12 @@ package mayaascii
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/nrtkbb/bufscan"
@@ -22,41 +25,75 @@ type Object struct {
 
 	cmds        []*Cmd
 	connections Connections
+	namespaces  map[string]struct{}
+
+	referenceEditsApplied bool
+	failedReferenceEdits  map[fmt.Stringer]struct{}
+
+	// codeset is the codeset of the file decoded by Open.
+	codeset string
+	// path is the file of Open or the resolved path of a reference, the
+	// root of the cycle detection of LoadReferences.
+	path string
 }
 
 func (o *Object) Unmarshal(reader io.Reader) error {
-	br := bufio.NewReader(reader)
+	return o.unmarshal(context.Background(), reader, UnmarshalOptions{})
+}
 
-	cmdBuilder := &CmdBuilder{}
-	lineCommentBuilder := &CmdBuilder{}
-	err := bufscan.BufScan(br, func(line string) error {
-		// Normally, it should be parsed with a proper
-		// tokenizer and lexer, but here it is simply
-		// treated as one token per line.
-		if TypeLineComment.HasPrefix(line) {
-			lineCommentBuilder.Append(line)
-			c := lineCommentBuilder.Parse()
-			o.cmds = append(o.cmds, c)
-			lineCommentBuilder.Clear()
-			cmdBuilder.lineNo++
-			return nil
-		}
-		cmdBuilder.Append(line)
-		lineCommentBuilder.lineNo++
-		if cmdBuilder.IsCmdEOF() {
-			c := cmdBuilder.Parse()
-			o.cmds = append(o.cmds, c)
-			cmdBuilder.Clear()
-		}
-		return nil
-	})
+// UnmarshalLazy is Unmarshal that keeps the setAttr commands as Raw and
+// tokenizes and decodes them on first access like Attr.GetAttrValue. The
+// decoded values are the same as Unmarshal. A value that can't be decoded
+// removes its Attr and the rest of the attributes of the node like
+// Unmarshal, on first access of an attribute of the node, and the removed
+// Attrs have the error.
+func (o *Object) UnmarshalLazy(reader io.Reader) error {
+	return o.unmarshal(context.Background(), reader, UnmarshalOptions{Lazy: true})
+}
+
+// UnmarshalWithOptions is Unmarshal with the limits for untrusted input.
+// When the input exceeds a limit, it returns *LimitError and o has none
+// of the commands.
+func (o *Object) UnmarshalWithOptions(reader io.Reader, opts UnmarshalOptions) error {
+	return o.unmarshal(context.Background(), reader, opts)
+}
+
+// UnmarshalContext is UnmarshalWithOptions that stops when ctx is done and
+// reports the progress to opts.Progress. When ctx is done, it returns the
+// error of ctx and o may have a part of the nodes.
+func (o *Object) UnmarshalContext(ctx context.Context, reader io.Reader, opts UnmarshalOptions) error {
+	return o.unmarshal(ctx, reader, opts)
+}
+
+// unmarshal tokenizes and parses the commands with opts.Workers
+// goroutines. The order-dependent steps, grouping the commands of a node
+// and inheriting the previous setAttr, stay sequential.
+func (o *Object) unmarshal(ctx context.Context, reader io.Reader, opts UnmarshalOptions) error {
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	pr := newProgress(opts.Progress)
+	cmds, err := scanCmds(ctx, reader, workers, newLimiter(opts), pr, opts.Lazy)
 	if err != nil {
 		return err
 	}
+	o.cmds = append(o.cmds, cmds...)
 
 	p := New(o.cmds)
 	p.o = o
+	p.ctx = ctx
+	p.progress = pr
+	p.workers = workers
+	p.lazy = opts.Lazy
+	if pr != nil {
+		pr.p.TotalCmds = len(cmds)
+		pr.report()
+	}
 	p.ParseCmds()
+	if p.ctxErr != nil {
+		return p.ctxErr
+	}
 	if !p.CheckErrors() {
 		return nil
 	}
@@ -75,7 +112,7 @@ func (o *Object) UnmarshalFocus(reader io.Reader, focusCommands CommandTypes) er
 	lineCommentBuilder := &CmdBuilder{}
 	isFocus := false
 	err := bufscan.BufScan(br, func(line string) error {
-		if TypeLineComment.HasPrefix(line) {
+		if !cmdBuilder.isInString() && TypeLineComment.HasPrefix(line) {
 			lcc := "//"
 			if focusCommands.InHasPrefix(&lcc) {
 				lineCommentBuilder.Append(line)
@@ -108,6 +145,7 @@ func (o *Object) UnmarshalFocus(reader io.Reader, focusCommands CommandTypes) er
 
 	p := New(o.cmds)
 	p.o = o
+	p.workers = runtime.GOMAXPROCS(0)
 	p.ParseCmds()
 	if !p.CheckErrors() {
 		return nil
@@ -116,6 +154,30 @@ func (o *Object) UnmarshalFocus(reader io.Reader, focusCommands CommandTypes) er
 	return nil
 }
 
+func (o *Object) indexOfCmd(c *Cmd) int {
+	for i, cmd := range o.cmds {
+		if cmd == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *Object) insertCmd(i int, c *Cmd) {
+	o.cmds = append(o.cmds, nil)
+	copy(o.cmds[i+1:], o.cmds[i:])
+	o.cmds[i] = c
+}
+
+func (o *Object) removeCmd(c *Cmd) bool {
+	i := o.indexOfCmd(c)
+	if i == -1 {
+		return false
+	}
+	o.cmds = append(o.cmds[:i], o.cmds[i+1:]...)
+	return true
+}
+
 func (o *Object) GetNode(n string) (*Node, error) {
 	node, ok := o.Nodes[n]
 	if !ok {
@@ -137,6 +199,17 @@ func (o *Object) GetNodes(nodeType string) ([]*Node, error) {
 	return results, nil
 }
 
+func (o *Object) sortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(o.Nodes))
+	for _, n := range o.Nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].createNodeCmd.LineNo < nodes[j].createNodeCmd.LineNo
+	})
+	return nodes
+}
+
 type LineComment struct {
 	lineCommentCmd *LineCommentCmd
 }
@@ -156,6 +229,8 @@ func (bc BlockComment) GetComment() string {
 type File struct {
 	Parent   *File
 	Children []*File
+	// Object is the parsed referenced file. It is set by LoadReferences.
+	Object *Object
 
 	fileCmd *FileCmd
 }
@@ -196,6 +271,37 @@ func (f File) IsDeferReference() bool {
 	return f.fileCmd.DeferReference
 }
 
+// IsReferenceDepthInfo reports whether the file is the "file -rdi" prelude
+// that describes the reference hierarchy. The references to load are the
+// "file -r" files.
+func (f File) IsReferenceDepthInfo() bool {
+	return f.fileCmd.IsReferenceDepthInfo()
+}
+
+// IsLoaded reports whether the reference is loaded when the scene is
+// opened, that is "file -r" without "-dr 1".
+func (f File) IsLoaded() bool {
+	return f.fileCmd.Reference && !f.fileCmd.DeferReference
+}
+
+// GetOptionValue returns the value of key in the -op flag like "v=0;".
+func (f File) GetOptionValue(key string) (string, bool) {
+	for _, o := range f.fileCmd.OptionValues {
+		if o.Key == key {
+			return o.Value, true
+		}
+	}
+	return "", false
+}
+
+func (f File) GetSharedNodes() []string {
+	return f.fileCmd.SharedNodes
+}
+
+func (f File) GetLoadReferenceDepth() string {
+	return f.fileCmd.LoadReferenceDepth
+}
+
 type FileInfo struct {
 	fileInfoCmd *FileInfoCmd
 }
@@ -210,7 +316,7 @@ func (fi FileInfo) GetValue() string {
 
 type Require struct {
 	Nodes []*Node
-	Data  []*Node // TODO: 何もセットしてない
+	Data  []*Node
 
 	requireCmd *RequiresCmd
 }
@@ -260,7 +366,7 @@ func (n *Node) GetAttr(name string) *Attr {
 }
 
 func (n *Node) GetUUID() (string, error) {
-	if n.renameCmd.UUID && n.renameCmd.To != nil {
+	if n.renameCmd != nil && n.renameCmd.UUID && n.renameCmd.To != nil {
 		return *n.renameCmd.To, nil
 	}
 	return "", errors.New(fmt.Sprintf("%s has not UUID", n.GetName()))
@@ -275,12 +381,18 @@ func (n *Node) Remove() error {
 		return errors.New(fmt.Sprintf("%s was already deleted", n.GetName()))
 	}
 	for _, c := range n.Children {
+		if c.isDeleted {
+			continue
+		}
 		err := c.Remove()
 		if err != nil {
 			return err
 		}
 	}
 	for _, a := range n.Attrs {
+		if a.isDeleted {
+			continue
+		}
 		err := a.Remove()
 		if err != nil {
 			return err
@@ -376,6 +488,7 @@ type Attr struct {
 	Node *Node
 
 	attrCmd   AttrCmd
+	lazy      *lazySetAttrs
 	err       error
 	isDeleted bool
 }
@@ -385,21 +498,36 @@ func (a *Attr) GetName() string {
 }
 
 func (a *Attr) IsChannelBox() bool {
+	a.decode()
 	return a.attrCmd.IsChannelBox()
 }
 
 func (a *Attr) IsKeyable() bool {
+	a.decode()
 	return a.attrCmd.IsKeyable()
 }
 
 func (a *Attr) GetAttrType() SetAttrType {
+	a.decode()
 	return a.attrCmd.GetAttrType()
 }
 
 func (a *Attr) GetAttrValue() []AttrValue {
+	a.decode()
 	return a.attrCmd.GetAttrValue()
 }
 
+func (a *Attr) getCmd() *Cmd {
+	a.decode()
+	switch ac := a.attrCmd.(type) {
+	case *SetAttrCmd:
+		return ac.Cmd
+	case *AddAttrCmd:
+		return ac.Cmd
+	}
+	return nil
+}
+
 func (a *Attr) Remove() error {
 	if a.isDeleted {
 		return errors.New(fmt.Sprintf("%s.%s was already deleted",
@@ -410,6 +538,7 @@ func (a *Attr) Remove() error {
 }
 
 func (a *Attr) String() (string, error) {
+	a.decode()
 	return a.GetName(), a.err
 }
 
@@ -419,6 +548,15 @@ type Parser struct {
 	cmds []*Cmd
 	cur  int
 
+	workers int
+	lazy    bool
+	parsed  []parsedAttr
+
+	// ctx stops ParseCmds when it is done. ctxErr is the error of ctx.
+	ctx      context.Context
+	ctxErr   error
+	progress *progress
+
 	CurCmd  *Cmd
 	PeekCmd *Cmd
 }
@@ -427,6 +565,7 @@ func New(cmds []*Cmd) *Parser {
 	p := &Parser{
 		cmds: cmds,
 		cur:  -1,
+		ctx:  context.Background(),
 	}
 	p.NextCmd()
 	p.NextCmd()
@@ -434,7 +573,13 @@ func New(cmds []*Cmd) *Parser {
 }
 
 func (p *Parser) ParseCmds() {
+	if 1 < p.workers {
+		p.parseAttrs(p.workers)
+	}
 	for p.CurCmd != nil {
+		if p.ctxErr = checkContext(p.ctx); p.ctxErr != nil {
+			return
+		}
 		var err error
 		switch p.CurCmd.Type {
 		case TypeLineComment:
@@ -461,7 +606,30 @@ func (p *Parser) ParseCmds() {
 			p.errs = append(p.errs, err.Error())
 		}
 		p.NextCmd()
+		p.reportProgress(false)
 	}
+	p.reportProgress(true)
+}
+
+// reportProgress reports the commands parsed so far every
+// progressInterval commands, or always if force.
+func (p *Parser) reportProgress(force bool) {
+	if p.progress == nil {
+		return
+	}
+	// CurCmd is the next command to parse.
+	cmds := p.cur - 1
+	if len(p.cmds) < cmds {
+		cmds = len(p.cmds)
+	}
+	if !force && cmds-p.progress.p.Cmds < progressInterval {
+		return
+	}
+	p.progress.p.Cmds = cmds
+	if p.o != nil {
+		p.progress.p.Nodes = len(p.o.Nodes)
+	}
+	p.progress.report()
 }
 
 func (p *Parser) CheckErrors() bool {
@@ -493,13 +661,16 @@ func (p *Parser) parseBlockComments() error {
 }
 
 func (p *Parser) parseFiles() error {
-	f := ParseFile(p.CurCmd)
+	f, err := ParseFile(p.CurCmd)
 	file := &File{
 		Parent:   nil,
 		Children: nil,
 		fileCmd:  f,
 	}
 	p.o.Files = append(p.o.Files, file)
+	if err != nil {
+		return err
+	}
 
 	if len(p.o.Files) == 1 {
 		return nil
@@ -524,7 +695,10 @@ func (p *Parser) parseFiles() error {
 }
 
 func (p *Parser) parseFileInfos() error {
-	fi := ParseFileInfo(p.CurCmd)
+	fi, err := ParseFileInfo(p.CurCmd)
+	if err != nil {
+		return err
+	}
 	fileInfo := &FileInfo{
 		fileInfoCmd: fi,
 	}
@@ -533,7 +707,10 @@ func (p *Parser) parseFileInfos() error {
 }
 
 func (p *Parser) parseRequires() error {
-	rq := ParseRequires(p.CurCmd)
+	rq, err := ParseRequires(p.CurCmd)
+	if err != nil {
+		return err
+	}
 	requires := &Require{
 		Nodes:      []*Node{},
 		Data:       []*Node{},
@@ -544,7 +721,10 @@ func (p *Parser) parseRequires() error {
 }
 
 func (p *Parser) parseCreateNode() error {
-	cn := ParseCreateNode(p.CurCmd)
+	cn, err := ParseCreateNode(p.CurCmd)
+	if err != nil {
+		return err
+	}
 	node := &Node{
 		object:        p.o,
 		createNodeCmd: cn,
@@ -553,6 +733,7 @@ func (p *Parser) parseCreateNode() error {
 		return errors.New(fmt.Sprintf("Already found node ... %s", node.GetName()))
 	}
 	p.o.Nodes[node.GetName()] = node
+	p.o.bindRequireNode(node)
 
 	if cn.Parent != nil {
 		// reverse loop.
@@ -572,7 +753,8 @@ func (p *Parser) parseCreateNode() error {
 
 	for p.PeekCmdIs(TypeAddAttr) {
 		p.NextCmd()
-		ad, err := ParseAddAttr(p.CurCmd)
+		p.o.bindRequireData(node, p.CurCmd)
+		ad, err := p.parseAddAttr()
 		if err != nil {
 			return err
 		}
@@ -586,12 +768,19 @@ func (p *Parser) parseCreateNode() error {
 	var setAttrCmds []*SetAttrCmd
 	for p.PeekCmdIs(TypeSetAttr) {
 		p.NextCmd()
+		if p.lazy {
+			attrName, dataType := getSetAttrHead(p.CurCmd)
+			p.o.bindRequireDataType(node, dataType)
+			node.Attrs = append(node.Attrs, p.newLazySetAttr(node, &node.Attrs, attrName))
+			continue
+		}
+		p.o.bindRequireData(node, p.CurCmd)
 		var sa *SetAttrCmd
 		var err error
 		if len(setAttrCmds) == 0 {
-			sa, err = ParseSetAttr(p.CurCmd, nil)
+			sa, err = p.parseSetAttr(nil)
 		} else {
-			sa, err = ParseSetAttr(p.CurCmd, setAttrCmds[len(setAttrCmds)-1])
+			sa, err = p.parseSetAttr(setAttrCmds[len(setAttrCmds)-1])
 		}
 		if err != nil {
 			return err
@@ -604,22 +793,6 @@ func (p *Parser) parseCreateNode() error {
 		node.Attrs = append(node.Attrs, a)
 	}
 
-	isPluginsNode := false
-	if len(p.o.Requires) != 0 {
-		for _, r := range p.o.Requires {
-			for _, nt := range r.GetNodeTypes() {
-				if node.GetType() == nt {
-					r.Nodes = append(r.Nodes, node)
-					isPluginsNode = true
-					break
-				}
-			}
-			if isPluginsNode {
-				break
-			}
-		}
-	}
-
 	return nil
 }
 
@@ -648,7 +821,7 @@ func (p *Parser) parseSelect() error {
 
 	for p.PeekCmdIs(TypeAddAttr) {
 		p.NextCmd()
-		ad, err := ParseAddAttr(p.CurCmd)
+		ad, err := p.parseAddAttr()
 		if err != nil {
 			return nil
 		}
@@ -661,12 +834,17 @@ func (p *Parser) parseSelect() error {
 	var setAttrs []*SetAttrCmd
 	for p.PeekCmdIs(TypeSetAttr) {
 		p.NextCmd()
+		if p.lazy {
+			attrName, _ := getSetAttrHead(p.CurCmd)
+			sel.Attrs = append(sel.Attrs, p.newLazySetAttr(nil, &sel.Attrs, attrName))
+			continue
+		}
 		var at *SetAttrCmd
 		var err error
 		if len(setAttrs) == 0 {
-			at, err = ParseSetAttr(p.CurCmd, nil)
+			at, err = p.parseSetAttr(nil)
 		} else {
-			at, err = ParseSetAttr(p.CurCmd, setAttrs[len(setAttrs)-1])
+			at, err = p.parseSetAttr(setAttrs[len(setAttrs)-1])
 		}
 		if err != nil {
 			return err
@@ -0,0 +1,150 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func getNamespaceTestMa() string {
+	return `//Maya ASCII 2019 scene
+//Name: namespace.ma
+file -rdi 1 -ns "char" -rfn "charRN" -op "v=0;" -typ "mayaAscii" "C:/char.ma";
+file -rdi 2 -ns "rig" -rfn "char:rigRN" -op "v=0;" -typ "mayaAscii" "C:/rig.ma";
+file -r -ns "char" -dr 1 -rfn "charRN" -op "v=0;" -typ "mayaAscii" "C:/char.ma";
+requires maya "2019";
+createNode transform -n "set:group1";
+createNode transform -n "set:sub:pCube1" -p "set:group1";
+createNode transform -n "pCube1";
+createNode reference -n "charRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"charRN"
+		"charRN" 2
+		2 "|char:root|char:rig:ctrl" "visibility" " 0"
+		5 4 "charRN" "|char:root.translateX" "charRN.placeHolderList[1]" "";
+connectAttr "set:sub:pCube1.t" "pCube1.t";
+// End of namespace.ma`
+}
+
+func TestObject_Namespaces(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getNamespaceTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := mo.Namespaces()
+	if len(root.Children) != 2 {
+		t.Fatalf("got len(root.Children) %d, wont 2", len(root.Children))
+	}
+	char, set := root.Children[0], root.Children[1]
+	for _, d := range []stringTestData{
+		{"char.GetFullName()", char.GetFullName(), "char"},
+		{"char.Children[0].GetFullName()", char.Children[0].GetFullName(), "char:rig"},
+		{"set.GetFullName()", set.GetFullName(), "set"},
+		{"set.Nodes[0].GetName()", set.Nodes[0].GetName(), "set:group1"},
+		{"set.Children[0].GetFullName()", set.Children[0].GetFullName(), "set:sub"},
+		{"set.Children[0].Nodes[0].GetName()",
+			set.Children[0].Nodes[0].GetName(), "set:sub:pCube1"},
+	} {
+		stringTester(d, t)
+	}
+	intTester(intTestData{"len(root.Nodes)", len(root.Nodes), 2}, t)
+}
+
+func TestObject_RenameNamespace(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getNamespaceTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.RenameNamespace("char", "set"); err == nil {
+		t.Error("got nil, wont already exists error")
+	}
+	if err := mo.RenameNamespace("char", "hero"); err != nil {
+		t.Fatal(err)
+	}
+	charRN, _ := mo.GetNode("charRN")
+	ed, err := ToAttrDataReferenceEdits(charRN.GetAttr(".ed").GetAttrValue())
+	if err != nil {
+		t.Fatal(err)
+	}
+	re := ed[0].ReferenceEdits[0]
+	for _, d := range []stringTestData{
+		{"mo.Files[0].GetNamespace()", mo.Files[0].GetNamespace(), "hero"},
+		{"mo.Files[1].GetNamespace()", mo.Files[1].GetNamespace(), "rig"},
+		{"mo.Files[1].GetReferenceNode()", mo.Files[1].GetReferenceNode(), "hero:rigRN"},
+		{"mo.Files[2].GetNamespace()", mo.Files[2].GetNamespace(), "hero"},
+		{"mo.Files[2].Raw", mo.Files[2].fileCmd.Raw,
+			`file -r -ns "hero" -dr 1 -rfn "charRN" -op "v=0;" -typ "mayaAscii" "C:/char.ma";`},
+		{"re.SetAttrs[0].Node", re.SetAttrs[0].Node, "|hero:root|hero:rig:ctrl"},
+		{"re.ConnectAttrs[0].SourcePlug", re.ConnectAttrs[0].SourcePlug,
+			"|hero:root.translateX"},
+		{"mo.Namespaces().Children[0].Children[0].GetFullName()",
+			mo.Namespaces().Children[0].Children[0].GetFullName(), "hero:rig"},
+	} {
+		stringTester(d, t)
+	}
+
+	if err := mo.RenameNamespace("set:sub", "set:inner"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mo.GetNode("set:inner:pCube1"); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"connections[0].SrcNode",
+		mo.connections.source[0].SrcNode, "set:inner:pCube1"}, t)
+}
+
+func TestObject_RemoveNamespace(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getNamespaceTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.RemoveNamespace("set", false); err == nil {
+		t.Error("got nil, wont not empty error")
+	}
+	if err := mo.RemoveNamespace("char", true); err == nil {
+		t.Error("got nil, wont reference namespace error")
+	}
+	if err := mo.RemoveNamespace("set:sub", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.RemoveNamespace("set", true); err != nil {
+		t.Fatal(err)
+	}
+	group1, err := mo.GetNode("group1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pCube2, err := mo.GetNode("pCube2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []stringTestData{
+		{"pCube2 parent", *pCube2.createNodeCmd.Parent, "group1"},
+		{"pCube2.Parent", pCube2.Parent.GetName(), group1.GetName()},
+		{"pCube2 Raw", pCube2.createNodeCmd.Raw,
+			`createNode transform -n "pCube2" -p "group1";`},
+		{"connections[0].Raw", mo.connections.source[0].Raw,
+			`connectAttr "pCube2.t" "pCube1.t";`},
+	} {
+		stringTester(d, t)
+	}
+	if len(mo.Namespaces().Children) != 1 {
+		t.Errorf("got len(Namespaces().Children) %d, wont 1",
+			len(mo.Namespaces().Children))
+	}
+
+	if err := mo.AddNamespace("a:b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.AddNamespace("a:b"); err == nil {
+		t.Error("got nil, wont already exists error")
+	}
+	if err := mo.RemoveNamespace("a", false); err == nil {
+		t.Error("got nil, wont not empty error")
+	}
+	if err := mo.RemoveNamespace("a:b", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.AddNamespace("1a"); err == nil {
+		t.Error("got nil, wont invalid namespace error")
+	}
+}
@@ -0,0 +1,109 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func getRenameTestMa() string {
+	return `//Maya ASCII 2019 scene
+//Name: rename.ma
+file -rdi 1 -ns "ref" -rfn "refRN" -op "v=0;" -typ "mayaAscii" "C:/ref.ma";
+file -r -ns "ref" -dr 1 -rfn "refRN" -op "v=0;" -typ "mayaAscii" "C:/ref.ma";
+requires maya "2019";
+createNode transform -n "group1";
+createNode transform -n "pCube1" -p "group1";
+createNode mesh -n "pCubeShape1" -p "|group1|pCube1";
+createNode transform -n "pCube2";
+createNode polyCube -n "polyCube1";
+createNode reference -n "refRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"refRN"
+		"refRN" 3
+		0 "|ref:root" "|group1|pCube1" "-s -r "
+		2 "|group1|pCube1" "visibility" " 0"
+		5 4 "refRN" "|group1|pCube1.translateX" "refRN.placeHolderList[1]" "";
+select -ne :pCube1;
+connectAttr "polyCube1.out" "|group1|pCube1|pCubeShape1.i";
+connectAttr "pCube1.t" "pCube2.t";
+// End of rename.ma`
+}
+
+func TestNode_Rename(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getRenameTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pCube1, err := mo.GetNode("pCube1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := pCube1.Rename("pCube2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"conflict resolved name", name, "pCube3"}, t)
+	stringTester(stringTestData{"pCube1.GetName()", pCube1.GetName(), "pCube3"}, t)
+	if _, err := mo.GetNode("pCube1"); err == nil {
+		t.Error("got pCube1, wont not found")
+	}
+	if n, err := mo.GetNode("pCube3"); err != nil || n != pCube1 {
+		t.Errorf("got %v, wont pCube3", n)
+	}
+	stringTester(stringTestData{"pCube1 Raw", pCube1.createNodeCmd.Raw,
+		`createNode transform -n "pCube3" -p "group1";`}, t)
+
+	shape, _ := mo.GetNode("pCubeShape1")
+	stringTester(stringTestData{"pCubeShape1 parent",
+		*shape.createNodeCmd.Parent, "|group1|pCube3"}, t)
+	stringTester(stringTestData{"pCubeShape1 Raw", shape.createNodeCmd.Raw,
+		`createNode mesh -n "pCubeShape1" -p "|group1|pCube3";`}, t)
+
+	conns := mo.connections.source
+	for _, d := range []stringTestData{
+		{"conns[0].DstNode", conns[0].DstNode, "|group1|pCube3|pCubeShape1"},
+		{"conns[0].Raw", conns[0].Raw,
+			`connectAttr "polyCube1.out" "|group1|pCube3|pCubeShape1.i";`},
+		{"conns[1].SrcNode", conns[1].SrcNode, "pCube3"},
+		{"conns[1].DstNode", conns[1].DstNode, "pCube2"},
+		{"mo.Selects[0].GetName()", mo.Selects[0].GetName(), ":pCube3"},
+		{"mo.Selects[0].Raw", mo.Selects[0].selectCmd.Raw, "select -ne :pCube3;"},
+	} {
+		stringTester(d, t)
+	}
+
+	refRN, _ := mo.GetNode("refRN")
+	ed, err := ToAttrDataReferenceEdits(refRN.GetAttr(".ed").GetAttrValue())
+	if err != nil {
+		t.Fatal(err)
+	}
+	re := ed[0].ReferenceEdits[0]
+	for _, d := range []stringTestData{
+		{"re.Parents[0].NodeB", re.Parents[0].NodeB, "|group1|pCube3"},
+		{"re.SetAttrs[0].Node", re.SetAttrs[0].Node, "|group1|pCube3"},
+		{"re.ConnectAttrs[0].SourcePlug", re.ConnectAttrs[0].SourcePlug,
+			"|group1|pCube3.translateX"},
+	} {
+		stringTester(d, t)
+	}
+	if strings.Contains(refRN.GetAttr(".ed").getCmd().Raw, "pCube1") {
+		t.Errorf("got %s, wont renamed", refRN.GetAttr(".ed").getCmd().Raw)
+	}
+
+	if _, err := refRN.Rename("refRN_new"); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"mo.Files[0].GetReferenceNode()",
+		mo.Files[0].GetReferenceNode(), "refRN_new"}, t)
+	stringTester(stringTestData{"ed[0].TopReferenceNode",
+		ed[0].TopReferenceNode, "refRN_new"}, t)
+	stringTester(stringTestData{"re.ConnectAttrs[0].DistPlug",
+		re.ConnectAttrs[0].DistPlug, "refRN_new.placeHolderList[1]"}, t)
+
+	for _, invalid := range []string{"", "1abc", "a|b", "a.b", "a b"} {
+		if _, err := pCube1.Rename(invalid); err == nil {
+			t.Errorf("got nil, wont invalid name error for \"%s\"", invalid)
+		}
+	}
+}
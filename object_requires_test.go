@@ -0,0 +1,120 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func getRequiresTestMa() string {
+	return `//Maya ASCII 2019 scene
+//Name: requires.ma
+requires maya "2019";
+requires -nodeType "nearestPointOnMesh" "nearestPointOnMesh" "4.0";
+requires "stereoCamera" "10.0";
+requires -nodeType "unusedNode" "unusedPlugin" "1.0";
+requires -dataType "myData" "dataPlugin" "2.0";
+createNode transform -n "group1";
+createNode nearestPointOnMesh -n "nearestPointOnMesh1";
+createNode unknownPluginNode -n "unknown1";
+createNode reference -n "sharedReferenceNode";
+createNode transform -n "dataHolder";
+	setAttr ".cd" -type "myData" 1 2 3;
+// End of requires.ma`
+}
+
+func TestObject_AnalyzeRequires(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getRequiresTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataPlugin, err := mo.GetRequire("dataPlugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dataPlugin.Data) != 1 {
+		t.Fatalf("got len(dataPlugin.Data) %d, wont 1", len(dataPlugin.Data))
+	}
+	stringTester(stringTestData{"dataPlugin.Data[0].GetName()",
+		dataPlugin.Data[0].GetName(), "dataHolder"}, t)
+
+	report := mo.AnalyzeRequires()
+	var required []string
+	for _, r := range report.Required {
+		required = append(required, r.GetPluginName())
+	}
+	stringTester(stringTestData{"report.Required",
+		strings.Join(required, ","), "maya,nearestPointOnMesh,dataPlugin"}, t)
+
+	var unused []string
+	for _, r := range report.Unused {
+		unused = append(unused, r.GetPluginName())
+	}
+	stringTester(stringTestData{"report.Unused",
+		strings.Join(unused, ","), "stereoCamera,unusedPlugin"}, t)
+
+	stringTester(stringTestData{"report.UnknownNodeTypes",
+		strings.Join(report.UnknownNodeTypes, ","), "unknownPluginNode"}, t)
+
+	report = mo.AnalyzeRequiresWithNodeTypes(map[string]struct{}{
+		"transform": {}, "unknownPluginNode": {}})
+	stringTester(stringTestData{"AnalyzeRequiresWithNodeTypes",
+		strings.Join(report.UnknownNodeTypes, ","), "reference"}, t)
+}
+
+func TestObject_AnalyzeRequires_commonNodeTypes(t *testing.T) {
+	var b strings.Builder
+	for _, nt := range []string{"pairBlend", "standardSurface", "polySplitRing",
+		"polyExtrudeVertex", "polyMoveVertex", "polyTriangulate", "deltaMush",
+		"softMod", "polyMapSewMove"} {
+		b.WriteString("createNode " + nt + " -n \"" + nt + "1\";\n")
+	}
+	mo, err := Unmarshal(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"UnknownNodeTypes",
+		strings.Join(mo.AnalyzeRequires().UnknownNodeTypes, ","), ""}, t)
+}
+
+func TestObject_AddRemoveRequire(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getRequiresTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := mo.AddRequire("unknownPlugin", "3.0", []string{"unknownPluginNode"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Nodes) != 1 {
+		t.Fatalf("got len(r.Nodes) %d, wont 1", len(r.Nodes))
+	}
+	stringTester(stringTestData{"r.Nodes[0].GetName()",
+		r.Nodes[0].GetName(), "unknown1"}, t)
+	if len(mo.AnalyzeRequires().UnknownNodeTypes) != 0 {
+		t.Errorf("got UnknownNodeTypes %v, wont empty",
+			mo.AnalyzeRequires().UnknownNodeTypes)
+	}
+	stringTester(stringTestData{"mo.cmds[7].Raw", mo.cmds[7].Raw,
+		`requires -nodeType "unknownPluginNode" "unknownPlugin" "3.0";`}, t)
+
+	if _, err := mo.AddRequire("unknownPlugin", "3.0", nil, nil); err == nil {
+		t.Error("got nil, wont already exists error")
+	}
+
+	for _, name := range []string{"stereoCamera", "unusedPlugin"} {
+		if err := mo.RemoveRequire(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	intTester(intTestData{"len(mo.Requires)", len(mo.Requires), 4}, t)
+	for _, c := range mo.cmds {
+		if strings.Contains(c.Raw, "stereoCamera") {
+			t.Errorf("got %s, wont removed", c.Raw)
+		}
+	}
+	if err := mo.RemoveRequire("stereoCamera"); err == nil {
+		t.Error("got nil, wont not found error")
+	}
+}
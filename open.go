@@ -0,0 +1,139 @@
+package mayaascii
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/transform"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// codesetHeader is the header comment of the codeset like
+// "//Codeset: 932".
+const codesetHeader = "//Codeset:"
+
+// codesetEncodings is the encodings of the codesets other than UTF-8.
+// Maya on Windows writes the code page.
+var codesetEncodings = map[string]encoding.Encoding{
+	"932":          japanese.ShiftJIS,
+	"shift_jis":    japanese.ShiftJIS,
+	"sjis":         japanese.ShiftJIS,
+	"euc-jp":       japanese.EUCJP,
+	"936":          simplifiedchinese.GBK,
+	"gbk":          simplifiedchinese.GBK,
+	"949":          korean.EUCKR,
+	"euc-kr":       korean.EUCKR,
+	"950":          traditionalchinese.Big5,
+	"big5":         traditionalchinese.Big5,
+	"1252":         charmap.Windows1252,
+	"windows-1252": charmap.Windows1252,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// getCodesetEncoding returns the encoding of codeset, or nil for UTF-8
+// and the unknown codesets.
+func getCodesetEncoding(codeset string) encoding.Encoding {
+	return codesetEncodings[strings.ToLower(codeset)]
+}
+
+// Open opens the Maya ASCII file at path and unmarshals it. The file may
+// be compressed by gzip or zstd. The strings of the file in the codeset
+// of the "//Codeset:" header are decoded to UTF-8, and Marshal encodes
+// them to the codeset again.
+func Open(path string) (*Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := Decompress(f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	dr, codeset, err := DecodeCodeset(r)
+	if err != nil {
+		return nil, err
+	}
+	o, err := Unmarshal(dr)
+	if err != nil {
+		return nil, err
+	}
+	o.codeset = codeset
+	o.path = path
+	return o, nil
+}
+
+// Decompress returns the reader of r that decompresses gzip or zstd by the
+// magic bytes. An uncompressed r is read as is.
+func Decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return ioutil.NopCloser(br), nil
+}
+
+// DecodeCodeset returns the reader of r that decodes the codeset of the
+// "//Codeset:" header to UTF-8, and the codeset. The codeset is "" if r
+// has no header. UTF-8 and the unknown codesets are read as is.
+func DecodeCodeset(r io.Reader) (io.Reader, string, error) {
+	br := bufio.NewReader(r)
+	codeset, err := peekCodeset(br)
+	if err != nil {
+		return nil, "", err
+	}
+	e := getCodesetEncoding(codeset)
+	if e == nil {
+		return br, codeset, nil
+	}
+	return transform.NewReader(br, e.NewDecoder()), codeset, nil
+}
+
+// peekCodeset returns the codeset in the header comments at the head of
+// br without reading them.
+func peekCodeset(br *bufio.Reader) (string, error) {
+	head, err := br.Peek(br.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+	for _, line := range strings.Split(string(head), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasPrefix(line, "//") {
+			// The end of the header comments.
+			break
+		}
+		if strings.HasPrefix(line, codesetHeader) {
+			return strings.TrimSpace(line[len(codesetHeader):]), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,109 @@
+package mayaascii
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func getOpenTestMa(codeset string) string {
+	return "//Maya ASCII 2019 scene\n" +
+		"//Codeset: " + codeset + "\n" +
+		"requires maya \"2019\";\n" +
+		"createNode transform -n \"立方体\";\n" +
+		"\tsetAttr \".notes\" -type \"string\" \"日本語のメモ\";\n" +
+		"createNode transform -n \"削除\";\n"
+}
+
+func writeOpenTestFile(t *testing.T, name string, src []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	ma := getOpenTestMa("932")
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(ma)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(sjis))
+	gw.Close()
+	var zst bytes.Buffer
+	zw, err := zstd.NewWriter(&zst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Write([]byte(sjis))
+	zw.Close()
+
+	for _, d := range []struct {
+		name string
+		src  []byte
+	}{
+		{"test.ma", []byte(sjis)},
+		{"test.ma.gz", gz.Bytes()},
+		{"test.ma.zst", zst.Bytes()},
+	} {
+		o, err := Open(writeOpenTestFile(t, d.name, d.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stringTester(stringTestData{d.name + " codeset", o.GetCodeset(), "932"}, t)
+		n, err := o.GetNode("立方体")
+		if err != nil {
+			t.Fatal(err)
+		}
+		notes := n.GetAttr(".notes").GetAttrValue()[0].(*AttrString).String()
+		stringTester(stringTestData{d.name + " notes", notes, "日本語のメモ"}, t)
+
+		var b bytes.Buffer
+		if err := o.Marshal(&b); err != nil {
+			t.Fatal(err)
+		}
+		stringTester(stringTestData{d.name + " Marshal", b.String(), sjis}, t)
+	}
+}
+
+func TestOpen_UTF8(t *testing.T) {
+	ma := getOpenTestMa("UTF-8")
+	o, err := Open(writeOpenTestFile(t, "test.ma", []byte(ma)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"codeset", o.GetCodeset(), "UTF-8"}, t)
+	n, err := o.GetNode("削除")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Marshal", b.String(),
+		strings.TrimSuffix(ma, "createNode transform -n \"削除\";\n")}, t)
+}
+
+func TestObject_Marshal_Unencodable(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader("createNode transform -n \"a\";\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.codeset = "932"
+	o.cmds[0].Raw = "createNode transform -n \"😀\";"
+	var b bytes.Buffer
+	boolTester(boolTestData{"error", o.Marshal(&b) != nil, true}, t)
+}
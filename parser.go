@@ -10,83 +10,255 @@ import (
 
 func ParseLineComment(c *Cmd) *LineCommentCmd {
 	lc := LineCommentCmd{Cmd: c}
-	lc.Comment = lc.Token[1]
+	if 1 < len(lc.Token) {
+		lc.Comment = lc.Token[1]
+	}
 	return &lc
 }
 
 func ParseBlockComment(c *Cmd) *BlockCommentCmd {
 	bc := BlockCommentCmd{Cmd: c}
-	bc.Comment = bc.Token[1]
+	if 1 < len(bc.Token) {
+		bc.Comment = bc.Token[1]
+	}
 	return &bc
 }
 
-func ParseFile(c *Cmd) *FileCmd {
+// getToken returns (*token)[i], or an error when the command is shorter.
+func getToken(token *[]string, i int) (string, error) {
+	if i < 0 || len(*token) <= i {
+		return "", errors.New(fmt.Sprintf(
+			"expected a value at %d, but the command has %d tokens", i, len(*token)))
+	}
+	return (*token)[i], nil
+}
+
+// getTokens returns (*token)[start:end], or an error when the command is
+// shorter.
+func getTokens(token *[]string, start, end int) ([]string, error) {
+	if start < 0 || end < start || len(*token) < end {
+		return nil, errors.New(fmt.Sprintf(
+			"expected values from %d to %d, but the command has %d tokens",
+			start, end, len(*token)))
+	}
+	return (*token)[start:end], nil
+}
+
+// getCount returns the count of the values at i. The count is not negative
+// and not larger than the tokens, so it is safe to allocate.
+func getCount(token *[]string, i int) (int, error) {
+	t, err := getToken(token, i)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(t)
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 || len(*token) < count {
+		return 0, errors.New(fmt.Sprintf("invalid count %d", count))
+	}
+	return count, nil
+}
+
+// getFlagValue returns the value of the flag at i.
+func getFlagValue(c *Cmd, i int) (string, error) {
+	if len(c.Token) <= i+1 {
+		return "", errors.New(fmt.Sprintf("%s has no value. %s", c.Token[i], c.Raw))
+	}
+	return c.Token[i+1], nil
+}
+
+// trimQuote returns t without the double quotes at the both ends.
+func trimQuote(t string) string {
+	if 2 <= len(t) && t[0] == '"' && t[len(t)-1] == '"' {
+		return t[1 : len(t)-1]
+	}
+	return t
+}
+
+func ParseFile(c *Cmd) (*FileCmd, error) {
 	// [file, -rdi, 1, -ns, "ns", -rfn, "nsRN", -op, "v=0;", -typ, "mayaAscii", "path/to/file.ma"]
 	// [file, -r, -ns, "namespace", -dr, 1, -rfn, "nsRN", -op, "v=0;", -typ, "mayaAscii", "path/to/file.ma"]
 	f := FileCmd{Cmd: c}
+	if len(f.Token) < 2 {
+		return &f, errors.New(fmt.Sprintf("file has no path. %s", c.Raw))
+	}
 	f.Path = strings.Trim(f.Token[len(f.Token)-1], "\"")
-	var err error
-	for i := 1; i < len(f.Token)-1; i++ {
-		switch f.Token[i] {
-		case "-rdi":
-			f.ReferenceDepthInfo, err = strconv.Atoi(f.Token[i+1])
+	var errs []string
+	last := len(f.Token) - 1
+	value := func(i int) (string, bool) {
+		if i+1 >= last {
+			errs = append(errs, fmt.Sprintf("%s has no value", f.Token[i]))
+			return "", false
+		}
+		return strings.Trim(f.Token[i+1], "\""), true
+	}
+	for i := 1; i < last; i++ {
+		flag := f.Token[i]
+		switch flag {
+		case "-r", "-reference":
+			f.Reference = true
+		case "-gl", "-groupLocator":
+			f.GroupLocator = true
+		case "-iv", "-ignoreVersion":
+			f.IgnoreVersion = true
+		case "-pr", "-preserveReferences":
+			f.PreserveReferences = true
+		case "-es", "-exportSelected":
+			f.ExportSelected = true
+		case "-rdi", "-referenceDepthInfo":
+			v, ok := value(i)
+			if !ok {
+				continue
+			}
+			rdi, err := strconv.Atoi(v)
 			if err != nil {
-				log.Print(err)
-				return &f
+				errs = append(errs, fmt.Sprintf("%s %s", flag, err.Error()))
 			}
+			f.ReferenceDepthInfo = rdi
 			i++
-		case "-ns":
-			f.Namespace = strings.Trim(f.Token[i+1], "\"")
+		case "-dr", "-deferReference":
+			v, ok := value(i)
+			if !ok {
+				continue
+			}
+			dr, err := parseFileBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s %s", flag, err.Error()))
+			}
+			f.DeferReference = dr
 			i++
-		case "-rfn":
-			f.ReferenceNode = strings.Trim(f.Token[i+1], "\"")
+		case "-mnc", "-mergeNamespacesOnClash":
+			v, ok := value(i)
+			if !ok {
+				continue
+			}
+			mnc, err := parseFileBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s %s", flag, err.Error()))
+			}
+			f.MergeNamespacesOnClash = &mnc
 			i++
-		case "-op":
-			f.Options = strings.Trim(f.Token[i+1], "\"")
+		case "-ns", "-namespace":
+			f.Namespace, _ = value(i)
 			i++
-		case "-typ":
-			f.Type = strings.Trim(f.Token[i+1], "\"")
+		case "-rfn", "-referenceNode":
+			f.ReferenceNode, _ = value(i)
 			i++
-		case "-r":
-			f.Reference = true
-		case "-dr":
-			var dr int
-			dr, err = strconv.Atoi(f.Token[i+1])
-			if dr == 1 {
-				f.DeferReference = true
+		case "-op", "-options":
+			f.Options, _ = value(i)
+			f.OptionValues = ParseFileOptions(f.Options)
+			i++
+		case "-typ", "-type":
+			f.Type, _ = value(i)
+			i++
+		case "-rpr", "-renamingPrefix":
+			if v, ok := value(i); ok {
+				f.RenamingPrefix = &v
+			}
+			i++
+		case "-shd", "-sharedNodes":
+			if v, ok := value(i); ok {
+				f.SharedNodes = append(f.SharedNodes, v)
 			}
 			i++
+		case "-lrd", "-loadReferenceDepth":
+			f.LoadReferenceDepth, _ = value(i)
+			i++
+		case "-sns", "-swapNamespace":
+			if i+2 >= last {
+				errs = append(errs, fmt.Sprintf("%s has no value", flag))
+				i = last
+				continue
+			}
+			f.SwapNamespaces = append(f.SwapNamespaces, FileSwapNamespace{
+				From: strings.Trim(f.Token[i+1], "\""),
+				To:   strings.Trim(f.Token[i+2], "\""),
+			})
+			i += 2
+		default:
+			// The unknown flag or its value.
+			f.UnknownFlags = append(f.UnknownFlags, flag)
+		}
+	}
+	if len(errs) != 0 {
+		return &f, errors.New(fmt.Sprintf("file %s: %s",
+			f.Path, strings.Join(errs, ", ")))
+	}
+	return &f, nil
+}
+
+// parseFileBool parses the bool values of file, "1", "0" and "on", "off" etc.
+func parseFileBool(t string) (bool, error) {
+	switch t {
+	case "1":
+		return true, nil
+	case "0":
+		return false, nil
+	}
+	return isOnYesOrOffNo(t)
+}
+
+// ParseFileOptions decomposes the -op flag of file like "v=0;p=17;" into
+// key and value pairs. A key without "=" has an empty value.
+func ParseFileOptions(op string) []FileOption {
+	var options []FileOption
+	for _, kv := range strings.Split(op, ";") {
+		if kv == "" {
+			continue
+		}
+		o := FileOption{Key: kv}
+		if i := strings.Index(kv, "="); i != -1 {
+			o.Key = kv[:i]
+			o.Value = kv[i+1:]
 		}
+		options = append(options, o)
 	}
-	return &f
+	return options
 }
 
-func ParseFileInfo(c *Cmd) *FileInfoCmd {
+func ParseFileInfo(c *Cmd) (*FileInfoCmd, error) {
 	fi := &FileInfoCmd{Cmd: c}
-	fi.Name = strings.Trim(fi.Token[1], "\"")
-	fi.Value = strings.Trim(fi.Token[2], "\"")
-	return fi
+	if len(fi.Token) < 3 {
+		return fi, errors.New(fmt.Sprintf("fileInfo has no name and value. %s", c.Raw))
+	}
+	fi.Name = trimQuote(fi.Token[1])
+	fi.Value = trimQuote(fi.Token[2])
+	return fi, nil
 }
 
-func ParseWorkspace(c *Cmd) *WorkspaceCmd {
+func ParseWorkspace(c *Cmd) (*WorkspaceCmd, error) {
 	w := WorkspaceCmd{Cmd: c}
+	var errs []string
 	for i := 1; i < len(w.Token); i++ {
 		switch w.Token[i] {
 		case "-fr":
+			if len(w.Token) <= i+2 {
+				errs = append(errs, fmt.Sprintf("-fr has no rule and place"))
+				i = len(w.Token)
+				break
+			}
 			w.FileRule = strings.Trim(w.Token[i+1], "\"")
 			w.Place = strings.Trim(w.Token[i+2], "\"")
 			i += 2
 		default:
-			panic("this option can not parse yet " + w.Token[i])
+			errs = append(errs, fmt.Sprintf("this option can not parse yet %s", w.Token[i]))
 		}
 	}
-	return &w
+	if len(errs) != 0 {
+		return &w, errors.New(fmt.Sprintf("%s. %s", strings.Join(errs, ", "), c.Raw))
+	}
+	return &w, nil
 }
 
-func ParseRequires(c *Cmd) *RequiresCmd {
+func ParseRequires(c *Cmd) (*RequiresCmd, error) {
 	// max Token = [requires, -nodeType, "typeName1", -dataType, "typeName2", "pluginName", "version"]
 	// min Token = [requires, "pluginName", "version"]
 	r := RequiresCmd{Cmd: c}
+	if len(r.Token) < 3 {
+		return &r, errors.New(fmt.Sprintf("requires has no plugin and version. %s", c.Raw))
+	}
 	r.PluginName = strings.Trim(r.Token[len(r.Token)-2], "\"")
 	r.Version = strings.Trim(r.Token[len(r.Token)-1], "\"")
 	if len(r.Token) > 3 {
@@ -101,7 +273,7 @@ func ParseRequires(c *Cmd) *RequiresCmd {
 			}
 		}
 	}
-	return &r
+	return &r, nil
 }
 
 func ParseConnectAttr(c *Cmd) (*ConnectAttrCmd, error) {
@@ -111,8 +283,11 @@ func ParseConnectAttr(c *Cmd) (*ConnectAttrCmd, error) {
 		case "-f":
 			ca.Force = true
 		case "-l":
-			var lock bool
-			lock, err := isOnYesOrOffNo(ca.Token[i+1])
+			v, err := getFlagValue(c, i)
+			if err != nil {
+				return nil, err
+			}
+			lock, err := isOnYesOrOffNo(v)
 			if err != nil {
 				log.Print(err)
 				return nil, err
@@ -122,13 +297,20 @@ func ParseConnectAttr(c *Cmd) (*ConnectAttrCmd, error) {
 		case "-na":
 			ca.NextAvailable = true
 		case "-rd":
+			if _, err := getFlagValue(c, i); err != nil {
+				return nil, err
+			}
 			ca.ReferenceDest = &ca.Token[i+1]
 			i++
 		default:
 			// trim "nodeName.attrName" -> {node: nodeName, attr: .attrName}
-			dotIndex := strings.Index(ca.Token[i], ".")
-			node := ca.Token[i][1:dotIndex]
-			attr := ca.Token[i][dotIndex+1 : len(ca.Token[i])-1]
+			plug := trimQuote(ca.Token[i])
+			dotIndex := strings.Index(plug, ".")
+			if dotIndex == -1 {
+				return nil, errors.New(fmt.Sprintf("%s is not a plug. %s", ca.Token[i], c.Raw))
+			}
+			node := plug[:dotIndex]
+			attr := plug[dotIndex+1:]
 			if ca.SrcNode == "" {
 				ca.SrcNode = node
 				ca.SrcAttr = attr
@@ -141,17 +323,28 @@ func ParseConnectAttr(c *Cmd) (*ConnectAttrCmd, error) {
 	return ca, nil
 }
 
-func ParseCreateNode(c *Cmd) *CreateNodeCmd {
+func ParseCreateNode(c *Cmd) (*CreateNodeCmd, error) {
 	n := &CreateNodeCmd{Cmd: c}
+	if len(c.Token) < 2 {
+		return n, errors.New(fmt.Sprintf("createNode has no node type. %s", c.Raw))
+	}
 	n.NodeType = c.Token[1]
 	for i := 2; i < len(n.Token); i++ {
 		switch n.Token[i] {
 		case "-n":
+			v, err := getFlagValue(c, i)
+			if err != nil {
+				return n, err
+			}
 			i++
-			n.NodeName = n.Token[i][1 : len(c.Token[i])-1]
+			n.NodeName = trimQuote(v)
 		case "-p":
+			v, err := getFlagValue(c, i)
+			if err != nil {
+				return n, err
+			}
 			i++
-			p := n.Token[i][1 : len(c.Token[i])-1]
+			p := trimQuote(v)
 			n.Parent = &p
 		case "-s":
 			n.Shared = true
@@ -159,7 +352,7 @@ func ParseCreateNode(c *Cmd) *CreateNodeCmd {
 			n.SkipSelect = true
 		}
 	}
-	return n
+	return n, nil
 }
 
 func ParseRename(c *Cmd) *RenameCmd {
@@ -228,7 +421,8 @@ func ParseSelect(c *Cmd) *SelectCmd {
 func getAttrNameFromSetAttr(token *[]string) (int, string) {
 	for i := 1; i < len(*token); i++ {
 		t := (*token)[i]
-		if t[0] == '"' &&
+		if 3 <= len(t) &&
+			t[0] == '"' &&
 			t[1] == '.' &&
 			t[len(t)-1] == '"' {
 			// ".attr" -> .attr
@@ -238,11 +432,23 @@ func getAttrNameFromSetAttr(token *[]string) (int, string) {
 	return -1, ""
 }
 
+func getDataTypeFromAttrCmd(token *[]string) string {
+	// setAttr ".attr" -type "dataType" ...;
+	// addAttr -ln "attr" -dt "dataType";
+	for i := 1; i < len(*token)-1; i++ {
+		switch (*token)[i] {
+		case "-type", "-typ", "-dt", "-dataType":
+			return strings.Trim((*token)[i+1], "\"")
+		}
+	}
+	return ""
+}
+
 func isSameAttr(name1, name2 string) bool {
 	if name1 == name2 {
 		return true
 	}
-	if name2[len(name2)-1] != ']' {
+	if name1 == "" || name2 == "" || name2[len(name2)-1] != ']' {
 		return false
 	}
 	openIdx2 := strings.LastIndex(name2, "[")
@@ -314,6 +520,12 @@ func ParseSetAttr(c *Cmd, beforeSetAttr *SetAttrCmd) (*SetAttrCmd, error) {
 		}
 		v := sa.Token[i]
 		switch v {
+		case "-ch", "-cb", "-k", "-l", "-s", "-type":
+			if _, err := getFlagValue(c, i); err != nil {
+				return nil, err
+			}
+		}
+		switch v {
 		case "-av":
 			sa.AlteredValue = true
 		case "-ca":
@@ -325,6 +537,9 @@ func ParseSetAttr(c *Cmd, beforeSetAttr *SetAttrCmd) (*SetAttrCmd, error) {
 			if err != nil {
 				return nil, err
 			}
+			if ch < 0 {
+				return nil, errors.New(fmt.Sprintf("invalid capacity hint %d", ch))
+			}
 			uch := uint(ch)
 			sa.CapacityHint = &uch
 		case "-cb":
@@ -356,6 +571,9 @@ func ParseSetAttr(c *Cmd, beforeSetAttr *SetAttrCmd) (*SetAttrCmd, error) {
 			if err != nil {
 				return nil, err
 			}
+			if s < 0 {
+				return nil, errors.New(fmt.Sprintf("invalid size %d", s))
+			}
 			us := uint(s)
 			sa.Size = &us
 		case "-type":
@@ -468,7 +686,7 @@ func ParseInts(token ...string) ([]int, error) {
 		if err != nil {
 			value, err := isOnYesOrOffNo(t)
 			if err != nil {
-				panic(err)
+				return nil, err
 			}
 			if value {
 				result = append(result, 1)
@@ -505,7 +723,11 @@ func ParseShort2Long2(
 	} else {
 		end = start + 2
 	}
-	v, err := ParseInts((*token)[start:end]...)
+	t, err := getTokens(token, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	v, err := ParseInts(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -541,7 +763,11 @@ func ParseShort3Long3(token *[]string, start int, size *uint, at *SetAttrType) (
 	} else {
 		end = start + 3
 	}
-	v, err := ParseInts((*token)[start:end]...)
+	t, err := getTokens(token, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	v, err := ParseInts(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -573,13 +799,17 @@ func ParseShort3Long3(token *[]string, start int, size *uint, at *SetAttrType) (
 }
 
 func ParseInt32Array(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
 	if err != nil {
 		return nil, 0, err
 	}
 	a := make([]AttrValue, 1)
 	if numberOfArray != 0 {
-		result, err := ParseInts((*token)[start+1 : start+1+numberOfArray]...)
+		t, err := getTokens(token, start+1, start+1+numberOfArray)
+		if err != nil {
+			return nil, 0, err
+		}
+		result, err := ParseInts(t...)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -599,7 +829,11 @@ func ParseFloat2Double2(token *[]string, start int, size *uint, at *SetAttrType)
 	} else {
 		end = start + 2
 	}
-	v, err := ParseFloats((*token)[start:end]...)
+	t, err := getTokens(token, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	v, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -635,7 +869,11 @@ func ParseFloat3Double3(token *[]string, start int, size *uint, at *SetAttrType)
 	} else {
 		end = start + 3
 	}
-	v, err := ParseFloats((*token)[start:end]...)
+	t, err := getTokens(token, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	v, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -667,13 +905,17 @@ func ParseFloat3Double3(token *[]string, start int, size *uint, at *SetAttrType)
 }
 
 func ParseDoubleArray(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
 	if err != nil {
 		return nil, 0, err
 	}
 	a := make([]AttrValue, 1)
 	if numberOfArray != 0 {
-		f, err := ParseFloats((*token)[start+1 : start+1+numberOfArray]...)
+		t, err := getTokens(token, start+1, start+1+numberOfArray)
+		if err != nil {
+			return nil, 0, err
+		}
+		f, err := ParseFloats(t...)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -687,7 +929,11 @@ func ParseDoubleArray(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseMatrix(token *[]string, start int) ([]AttrValue, int, error) {
-	mat4x4, err := ParseFloats((*token)[start : start+16]...)
+	t, err := getTokens(token, start, start+16)
+	if err != nil {
+		return nil, 0, err
+	}
+	mat4x4, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -730,7 +976,11 @@ func ParseMatrixXform(token *[]string, start int) ([]AttrValue, int, error) {
 	// jointOrientW jointOrientX jointOrientY jointOrientZ
 	// inverseParentScaleX inverseParentScaleY inverseParentScaleZ
 	// compensateForParentScale
-	floats, err := ParseFloats((*token)[start+1 : start+37]...)
+	t, err := getTokens(token, start, start+38)
+	if err != nil {
+		return nil, 0, err
+	}
+	floats, err := ParseFloats(t[1:37]...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -738,7 +988,7 @@ func ParseMatrixXform(token *[]string, start int) ([]AttrValue, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	componentForParentScale, err := isOnYesOrOffNo((*token)[start+37])
+	componentForParentScale, err := isOnYesOrOffNo(t[37])
 	if err != nil {
 		return nil, 0, err
 	}
@@ -763,13 +1013,17 @@ func ParseMatrixXform(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParsePointArray(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
 	if err != nil {
 		return nil, 0, err
 	}
 	a := make([]AttrValue, 1)
 	if numberOfArray != 0 {
-		f, err := ParseFloats((*token)[start+1 : start+1+(numberOfArray*4)]...)
+		t, err := getTokens(token, start+1, start+1+(numberOfArray*4))
+		if err != nil {
+			return nil, 0, err
+		}
+		f, err := ParseFloats(t...)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -790,13 +1044,17 @@ func ParsePointArray(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseVectorArray(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
 	if err != nil {
 		return nil, 0, err
 	}
 	a := make([]AttrValue, 1)
 	if numberOfArray != 0 {
-		f, err := ParseFloats((*token)[start+1 : start+1+numberOfArray]...)
+		t, err := getTokens(token, start+1, start+1+numberOfArray)
+		if err != nil {
+			return nil, 0, err
+		}
+		f, err := ParseFloats(t...)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -828,26 +1086,38 @@ func ParseVectorArray(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseString(token *[]string, start int) ([]AttrValue, int, error) {
-	s := AttrString((*token)[start][1 : len((*token)[start])-1])
+	t, err := getToken(token, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	s := AttrString(trimQuote(t))
 	a := []AttrValue{&s}
 	return a, 1, nil
 }
 
 func ParseStringArray(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	t, err := getTokens(token, start+1, start+1+numberOfArray)
 	if err != nil {
 		return nil, 0, err
 	}
-	sa := AttrStringArray((*token)[start+1 : start+1+numberOfArray])
-	for i, s := range sa {
-		sa[i] = s[1 : len(s)-1]
+	sa := make(AttrStringArray, len(t))
+	for i, s := range t {
+		sa[i] = trimQuote(s)
 	}
 	a := []AttrValue{&sa}
 	return a, 1 + numberOfArray, nil
 }
 
 func ParseSphere(token *[]string, start int) ([]AttrValue, int, error) {
-	s, err := strconv.ParseFloat((*token)[start], 64)
+	t, err := getToken(token, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	s, err := strconv.ParseFloat(t, 64)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -857,7 +1127,11 @@ func ParseSphere(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseCone(token *[]string, start int) ([]AttrValue, int, error) {
-	f, err := ParseFloats((*token)[start : start+2]...)
+	t, err := getTokens(token, start, start+2)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -870,7 +1144,11 @@ func ParseCone(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseReflectanceRGB(token *[]string, start int) ([]AttrValue, int, error) {
-	f, err := ParseFloats((*token)[start : start+3]...)
+	t, err := getTokens(token, start, start+3)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -884,7 +1162,11 @@ func ParseReflectanceRGB(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseSpectrumRGB(token *[]string, start int) ([]AttrValue, int, error) {
-	f, err := ParseFloats((*token)[start : start+3]...)
+	t, err := getTokens(token, start, start+3)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -898,12 +1180,16 @@ func ParseSpectrumRGB(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseComponentList(token *[]string, start int) ([]AttrValue, int, error) {
-	numberOfArray, err := strconv.Atoi((*token)[start])
+	numberOfArray, err := getCount(token, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	t, err := getTokens(token, start+1, start+1+numberOfArray)
 	if err != nil {
 		return nil, 0, err
 	}
 	var cl AttrComponentList
-	for _, c := range (*token)[start+1 : start+1+numberOfArray] {
+	for _, c := range t {
 		cl = append(cl, strings.Trim(c, "\""))
 	}
 	a := []AttrValue{&cl}
@@ -911,7 +1197,7 @@ func ParseComponentList(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseAttributeAlias(token *[]string, start int) ([]AttrValue, int, error) {
-	if (*token)[start] != "{" {
+	if t, _ := getToken(token, start); t != "{" {
 		return nil, 0, errors.New("there was no necessary token")
 	}
 	var aaa []AttrAttributeAlias
@@ -919,6 +1205,9 @@ func ParseAttributeAlias(token *[]string, start int) ([]AttrValue, int, error) {
 		if (*token)[i] == "}" {
 			break
 		}
+		if len(*token) <= i+1 {
+			return nil, 0, errors.New("the attribute alias has no current name")
+		}
 		aaa = append(aaa, AttrAttributeAlias{
 			NewAlias:    (*token)[i],
 			CurrentName: (*token)[i+1],
@@ -932,7 +1221,11 @@ func ParseAttributeAlias(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseNurbsCurve(token *[]string, start int) ([]AttrValue, int, error) {
-	i1, err := ParseInts((*token)[start : start+3]...)
+	t, err := getTokens(token, start, start+6)
+	if err != nil {
+		return nil, 0, err
+	}
+	i1, err := ParseInts(t[0:3]...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -942,21 +1235,28 @@ func ParseNurbsCurve(token *[]string, start int) ([]AttrValue, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	isRational, err := isOnYesOrOffNo((*token)[start+3])
+	isRational, err := isOnYesOrOffNo(t[3])
 	if err != nil {
 		return nil, 0, err
 	}
-	i2, err := ParseInts((*token)[start+4 : start+6]...)
+	i2, err := ParseInts(t[4:6]...)
 	if err != nil {
 		return nil, 0, err
 	}
 	dimension := i2[0]
-	knotCount := i2[1]
-	kv, err := ParseFloats((*token)[start+6 : start+6+knotCount]...)
+	knotCount, err := getCount(token, start+5)
+	if err != nil {
+		return nil, 0, err
+	}
+	t, err = getTokens(token, start+6, start+6+knotCount)
+	if err != nil {
+		return nil, 0, err
+	}
+	kv, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
-	cvCount, err := strconv.Atoi((*token)[start+6+knotCount])
+	cvCount, err := getCount(token, start+6+knotCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -967,7 +1267,11 @@ func ParseNurbsCurve(token *[]string, start int) ([]AttrValue, int, error) {
 	if dimension == 3 {
 		divideCv += 1
 	}
-	cv, err := ParseFloats((*token)[start+7+knotCount : start+7+knotCount+(cvCount*divideCv)]...)
+	t, err = getTokens(token, start+7+knotCount, start+7+knotCount+(cvCount*divideCv))
+	if err != nil {
+		return nil, 0, err
+	}
+	cv, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1001,7 +1305,11 @@ func ParseNurbsCurve(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseNurbsSurface(token *[]string, start int) ([]AttrValue, int, error) {
-	i1, err := ParseInts((*token)[start : start+4]...)
+	t, err := getTokens(token, start, start+5)
+	if err != nil {
+		return nil, 0, err
+	}
+	i1, err := ParseInts(t[0:4]...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1015,29 +1323,43 @@ func ParseNurbsSurface(token *[]string, start int) ([]AttrValue, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	isRational, err := isOnYesOrOffNo((*token)[start+4])
-	uKnotCount, err := strconv.Atoi((*token)[start+5])
+	isRational, err := isOnYesOrOffNo(t[4])
+	if err != nil {
+		return nil, 0, err
+	}
+	uKnotCount, err := getCount(token, start+5)
+	if err != nil {
+		return nil, 0, err
+	}
+	t, err = getTokens(token, start+6, start+6+uKnotCount)
+	if err != nil {
+		return nil, 0, err
+	}
+	uKnotValues, err := ParseFloats(t...)
+	if err != nil {
+		return nil, 0, err
+	}
+	vKnotCount, err := getCount(token, start+6+uKnotCount)
 	if err != nil {
 		return nil, 0, err
 	}
-	uKnotValues, err := ParseFloats((*token)[start+6 : start+6+uKnotCount]...)
+	t, err = getTokens(token, start+7+uKnotCount, start+7+uKnotCount+vKnotCount)
 	if err != nil {
 		return nil, 0, err
 	}
-	vKnotCount, err := strconv.Atoi((*token)[start+6+uKnotCount])
+	vKnotValues, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
-	vKnotValues, err := ParseFloats(
-		(*token)[start+7+uKnotCount : start+7+uKnotCount+vKnotCount]...)
+	trim, err := getToken(token, start+7+uKnotCount+vKnotCount)
 	if err != nil {
 		return nil, 0, err
 	}
 	var isTrim *bool
-	if (*token)[start+7+uKnotCount+vKnotCount] == "\"TRIM\"" {
+	if trim == "\"TRIM\"" {
 		v := true
 		isTrim = &v
-	} else if (*token)[start+7+uKnotCount+vKnotCount] == "\"NOTRIM\"" {
+	} else if trim == "\"NOTRIM\"" {
 		v := false
 		isTrim = &v
 	}
@@ -1045,7 +1367,7 @@ func ParseNurbsSurface(token *[]string, start int) ([]AttrValue, int, error) {
 	if isTrim != nil {
 		cvStart++
 	}
-	cvCount, err := strconv.Atoi((*token)[cvStart])
+	cvCount, err := getCount(token, cvStart)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1053,7 +1375,11 @@ func ParseNurbsSurface(token *[]string, start int) ([]AttrValue, int, error) {
 	if isRational {
 		divideCv++
 	}
-	cv, err := ParseFloats((*token)[cvStart+1 : cvStart+1+(cvCount*divideCv)]...)
+	t, err = getTokens(token, cvStart+1, cvStart+1+(cvCount*divideCv))
+	if err != nil {
+		return nil, 0, err
+	}
+	cv, err := ParseFloats(t...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1089,11 +1415,15 @@ func ParseNurbsTrimface(token *[]string, start int) ([]AttrValue, int, error) {
 }
 
 func ParseCountInt(token *[]string, start int) ([]int, error) {
-	count, err := strconv.Atoi((*token)[start])
+	count, err := getCount(token, start)
+	if err != nil {
+		return nil, err
+	}
+	t, err := getTokens(token, start+1, start+1+count)
 	if err != nil {
 		return nil, err
 	}
-	result, err := ParseInts((*token)[start+1 : start+1+count]...)
+	result, err := ParseInts(t...)
 	if err != nil {
 		return nil, err
 	}
@@ -1119,6 +1449,13 @@ func ParsePolyFace(token *[]string, start int, size *uint) ([]AttrValue, int, er
 	i := -1
 	loop := true
 	for loop && len(*token) > switchNumber {
+		switch (*token)[switchNumber] {
+		case "h", "fc", "mc", "mu":
+			if i < 0 {
+				return nil, 0, errors.New(fmt.Sprintf(
+					"%s is not after f", (*token)[switchNumber]))
+			}
+		}
 		switch (*token)[switchNumber] {
 		case "f":
 			fe, err := ParseCountInt(token, switchNumber+1)
@@ -1149,7 +1486,11 @@ func ParsePolyFace(token *[]string, start int, size *uint) ([]AttrValue, int, er
 			pfs[i].FaceColor = fc
 			switchNumber += 2 + len(fc)
 		case "mc":
-			colorIndex, err := strconv.ParseInt((*token)[switchNumber+1], 10, 64)
+			t, err := getToken(token, switchNumber+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			colorIndex, err := strconv.ParseInt(t, 10, 64)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -1165,7 +1506,11 @@ func ParsePolyFace(token *[]string, start int, size *uint) ([]AttrValue, int, er
 			switchNumber += 3 + len(colorIDs)
 		case "mu":
 			var fuv AttrFaceUV
-			uvSet, err := strconv.Atoi((*token)[switchNumber+1])
+			t, err := getToken(token, switchNumber+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			uvSet, err := strconv.Atoi(t)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -1190,13 +1535,17 @@ func ParsePolyFace(token *[]string, start int, size *uint) ([]AttrValue, int, er
 }
 
 func ParseDataPolyComponent(token *[]string, start int) ([]AttrValue, int, error) {
-	if "Index_Data" != (*token)[start] {
+	t, err := getTokens(token, start, start+3)
+	if err != nil {
+		return nil, 0, err
+	}
+	if "Index_Data" != t[0] {
 		return nil, 0, errors.New(
 			"since the Index_Data did not exist, " +
 				"this token is an unknown dataPolyComponent")
 	}
 	var dpc AttrDataPolyComponent
-	switch (*token)[start+1] {
+	switch t[1] {
 	case "Edge":
 		dpc.PolyComponentType = DPCedge
 	case "Face":
@@ -1210,10 +1559,13 @@ func ParseDataPolyComponent(token *[]string, start int) ([]AttrValue, int, error
 			"it is an unknown dataPolyComponent " +
 				"that is neither Edge, Face, Vertex, UV")
 	}
-	count, err := strconv.Atoi((*token)[start+2])
+	count, err := getCount(token, start+2)
 	if err != nil {
 		return nil, 0, err
 	}
+	if _, err := getTokens(token, start+3, start+3+(count*2)); err != nil {
+		return nil, 0, err
+	}
 	dpc.IndexValue = map[int]float64{}
 	for i := 0; i < count; i++ {
 		index, err := strconv.Atoi((*token)[start+3+(i*2)])
@@ -1237,10 +1589,20 @@ func ParseMesh(_ *[]string, _ int) ([]AttrValue, int, error) {
 }
 
 func ParseLattice(token *[]string, start int) ([]AttrValue, int, error) {
-	c, err := ParseInts((*token)[start : start+4]...)
+	t, err := getTokens(token, start, start+4)
+	if err != nil {
+		return nil, 0, err
+	}
+	c, err := ParseInts(t...)
 	if err != nil {
 		return nil, 0, err
 	}
+	if c[3] < 0 || len(*token) < c[3] {
+		return nil, 0, errors.New(fmt.Sprintf("invalid count %d", c[3]))
+	}
+	if _, err := getTokens(token, start+4, start+4+(c[3]*3)); err != nil {
+		return nil, 0, err
+	}
 	la := AttrLattice{
 		DivisionS: c[0],
 		DivisionT: c[1],
@@ -1319,7 +1681,11 @@ func ParseAttr(token *[]string, start int, size *uint, attrType SetAttrType) ([]
 }
 
 func ParseAttrType(token *[]string, start int) (SetAttrType, error) {
-	typeString := (*token)[start][1 : len((*token)[start])-1]
+	t, err := getToken(token, start)
+	if err != nil {
+		return SetAttrTypeInvalid, err
+	}
+	typeString := trimQuote(t)
 	switch typeString {
 	case "short2":
 		return SetAttrTypeShort2, nil
@@ -1342,7 +1708,7 @@ func ParseAttrType(token *[]string, start int) (SetAttrType, error) {
 	case "doubleArray":
 		return SetAttrTypeDoubleArray, nil
 	case "matrix":
-		typeString2 := (*token)[start+1]
+		typeString2, _ := getToken(token, start+1)
 		if typeString2 == "\"xform\"" {
 			return SetAttrTypeMatrixXform, nil
 		} else {
@@ -1388,14 +1754,26 @@ func ParseAttrType(token *[]string, start int) (SetAttrType, error) {
 	return SetAttrTypeInvalid, errors.New("Invalid type " + typeString)
 }
 
+// addAttrBoolFlags is the flags of addAttr without a value.
+var addAttrBoolFlags = map[string]bool{
+	"-ci": true, "-cachedInternally": true,
+	"-ex": true, "-exists": true,
+	"-m": true, "-multi": true,
+	"-uac": true, "-usedAsColor": true,
+	"-uaf": true, "-usedAsFilename": true,
+	"-uap": true, "-usedAsProxy": true,
+}
+
 func ParseAddAttr(c *Cmd) (*AddAttrCmd, error) {
 	// addAttr -shortName ms -longName mass -defaultValue 1.0 -minValue 0.001 -maxValue 10000;
 	// addAttr -ci true -sn "liw" -ln "lockInfluenceWeights" -min 0 -max 1 -at "bool";
 	aa := &AddAttrCmd{Cmd: c}
-	fmt.Println(aa.Token)
 	for i := 1; i < len(aa.Token); i++ {
+		if isFlag(aa.Token[i]) && len(aa.Token) <= i+1 && !addAttrBoolFlags[aa.Token[i]] {
+			return nil, errors.New(fmt.Sprintf("%s has no value. %s", aa.Token[i], c.Raw))
+		}
 		switch aa.Token[i] {
-		case "-at", "attributeType":
+		case "-at", "-attributeType":
 			at, err := NewAddAttrAttributeType(strings.Trim(aa.Token[i+1], "\""))
 			if err != nil {
 				return nil, err
@@ -7,7 +7,10 @@ import (
 func TestMakeCreateNode_Min(t *testing.T) {
 	c := &CmdBuilder{}
 	c.Append(`createNode transform -n "nodeName";`)
-	cn := ParseCreateNode(c.Parse())
+	cn, err := ParseCreateNode(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
 	msg := `got CreateNodeCmd %s "%s", wont "%s"`
 	if cn.NodeType != "transform" {
 		t.Errorf(msg, "NodeType", cn.NodeType, "transform")
@@ -29,7 +32,10 @@ func TestMakeCreateNode_Min(t *testing.T) {
 func TestMakeCreateNode_Max(t *testing.T) {
 	c := &CmdBuilder{}
 	c.Append(`createNode camera -s -n "ns:grp|ns:cam|ns:camShape" -p "ns:grp|ns:cam";`)
-	cn := ParseCreateNode(c.Parse())
+	cn, err := ParseCreateNode(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
 	msg := `got CreateNodeCmd %s "%s", wont "%s"`
 	if cn.NodeType != "camera" {
 		t.Errorf(msg, "NodeType", cn.NodeName, "camera")
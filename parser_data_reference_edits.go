@@ -29,6 +29,9 @@ func (p *DataReferenceEditsParser) parseReferenceEdit() (*ReferenceEdit, error)
 }
 
 func (p *DataReferenceEditsParser) parseParent() (*RECmdParent, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseParentError: not enough tokens")
+	}
 	p.NextToken() // skip 0
 	prt := &RECmdParent{
 		NodeA: strings.Trim(*p.CurToken, "\""),
@@ -42,11 +45,14 @@ func (p *DataReferenceEditsParser) parseParent() (*RECmdParent, error) {
 		return nil, errors.New("parseParentError: not enough tokens")
 	}
 	p.NextToken() // skip nodeB
-	prt.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	prt.Arguments = trimQuote(*p.CurToken)
 	return prt, nil
 }
 
 func (p *DataReferenceEditsParser) parseAddAttr() (*RECmdAddAttr, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseAddAttrError: not enough tokens")
+	}
 	p.NextToken() // skip 1
 	aa := &RECmdAddAttr{
 		Node: *p.CurToken,
@@ -65,12 +71,15 @@ func (p *DataReferenceEditsParser) parseAddAttr() (*RECmdAddAttr, error) {
 		return nil, errors.New("parseAddAttrError: not enough tokens")
 	}
 	p.NextToken() // skip shot attr name
-	aa.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	aa.Arguments = trimQuote(*p.CurToken)
 	return aa, nil
 }
 
 func (p *DataReferenceEditsParser) parseSetAttr() (*RECmdSetAttr, error) {
 	//fmt.Println("setAttr 2 ", *p.CurToken)
+	if p.PeekToken == nil {
+		return nil, errors.New("parseSetAttrError: not enough tokens")
+	}
 	p.NextToken() // skip 2
 	//fmt.Println("setAttr Node ", *p.CurToken)
 	sa := &RECmdSetAttr{
@@ -87,11 +96,14 @@ func (p *DataReferenceEditsParser) parseSetAttr() (*RECmdSetAttr, error) {
 	}
 	p.NextToken() // skip attr name
 	//fmt.Println("setAttr Arguments ", *p.CurToken)
-	sa.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	sa.Arguments = trimQuote(*p.CurToken)
 	return sa, nil
 }
 
 func (p *DataReferenceEditsParser) parseDisconnectAttr() (*RECmdDisconnectAttr, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseDisconnectAttr: not enough tokens")
+	}
 	p.NextToken() // skip 3
 	da := &RECmdDisconnectAttr{
 		SourcePlug: strings.Trim(*p.CurToken, "\""),
@@ -105,11 +117,14 @@ func (p *DataReferenceEditsParser) parseDisconnectAttr() (*RECmdDisconnectAttr,
 		return nil, errors.New("parseDisconnectAttr: not enough tokens")
 	}
 	p.NextToken()
-	da.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	da.Arguments = trimQuote(*p.CurToken)
 	return da, nil
 }
 
 func (p *DataReferenceEditsParser) parseDeleteAttr() (*RECmdDeleteAttr, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseDeleteAttr: not enough tokens")
+	}
 	p.NextToken() // skip 4
 	da := &RECmdDeleteAttr{
 		Node: strings.Trim(*p.CurToken, "\""),
@@ -123,11 +138,14 @@ func (p *DataReferenceEditsParser) parseDeleteAttr() (*RECmdDeleteAttr, error) {
 		return nil, errors.New("parseDeleteAttr: not enough tokens")
 	}
 	p.NextToken()
-	da.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	da.Arguments = trimQuote(*p.CurToken)
 	return da, nil
 }
 
 func (p *DataReferenceEditsParser) parseConnectAttr() (*RECmdConnectAttr, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseConnectAttr: not enough tokens")
+	}
 	p.NextToken() // skip 5
 	magic, err := strconv.Atoi(*p.CurToken)
 	if err != nil {
@@ -169,11 +187,14 @@ func (p *DataReferenceEditsParser) parseConnectAttr() (*RECmdConnectAttr, error)
 		}
 		p.NextToken()
 	}
-	ca.Arguments = (*p.CurToken)[1:len(*p.CurToken)-1]
+	ca.Arguments = trimQuote(*p.CurToken)
 	return ca, nil
 }
 
 func (p *DataReferenceEditsParser) parseRelationship() (*RECmdRelationship, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseRelationship: not enough tokens")
+	}
 	p.NextToken() // skip 7
 	rs := &RECmdRelationship{
 		Type: strings.Trim(*p.CurToken, "\""),
@@ -211,6 +232,9 @@ func (p *DataReferenceEditsParser) parseRelationship() (*RECmdRelationship, erro
 }
 
 func (p *DataReferenceEditsParser) parseLock() (*RECmdLock, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseLock: not enough tokens")
+	}
 	p.NextToken() // skip 8
 	ulk := &RECmdLock{
 		Node: strings.Trim(*p.CurToken, "\""),
@@ -224,6 +248,9 @@ func (p *DataReferenceEditsParser) parseLock() (*RECmdLock, error) {
 }
 
 func (p *DataReferenceEditsParser) parseUnlock() (*RECmdUnlock, error) {
+	if p.PeekToken == nil {
+		return nil, errors.New("parseUnlock: not enough tokens")
+	}
 	p.NextToken() // skip 9
 	ulk := &RECmdUnlock{
 		Node: strings.Trim(*p.CurToken, "\""),
@@ -250,9 +277,6 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 			res = append(res, re)
 			if re != nil {
 				commandNum = (*re).CommandNum
-			} else {
-				fmt.Printf("commandNum == 0, referenceEdit is nil... %s %d %s %s %s %s\n",
-					*p.CurToken, p.cur, (*p.token)[p.cur-2], (*p.token)[p.cur-1], (*p.token)[p.cur], (*p.token)[p.cur+1])
 			}
 		} else if p.CurTokenIs(string(RETypePArent)) {
 			if res == nil {
@@ -266,6 +290,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.Parents = []*RECmdParent{}
 			}
 			re.Parents = append(re.Parents, prt)
+			re.edits = append(re.edits, prt)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeAddAttr)) {
 			if res == nil {
@@ -279,6 +304,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.AddAttrs = []*RECmdAddAttr{}
 			}
 			re.AddAttrs = append(re.AddAttrs, add)
+			re.edits = append(re.edits, add)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeSetAttr)) {
 			if res == nil {
@@ -292,6 +318,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.SetAttrs = []*RECmdSetAttr{}
 			}
 			re.SetAttrs = append(re.SetAttrs, set)
+			re.edits = append(re.edits, set)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeDisconnectAttr)) {
 			if res == nil {
@@ -305,6 +332,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.DisconnectAttrs = []*RECmdDisconnectAttr{}
 			}
 			re.DisconnectAttrs = append(re.DisconnectAttrs, dis)
+			re.edits = append(re.edits, dis)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeDeleteAttr)) {
 			if res == nil {
@@ -318,6 +346,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.DeleteAttrs = []*RECmdDeleteAttr{}
 			}
 			re.DeleteAttrs = append(re.DeleteAttrs, del)
+			re.edits = append(re.edits, del)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeConnectAttr)) && p.PeekTokenIsNumber() {
 			if res == nil {
@@ -331,6 +360,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.ConnectAttrs = []*RECmdConnectAttr{}
 			}
 			re.ConnectAttrs = append(re.ConnectAttrs, con)
+			re.edits = append(re.edits, con)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeRelationship)) {
 			if res == nil {
@@ -344,6 +374,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.Relationships = []*RECmdRelationship{}
 			}
 			re.Relationships = append(re.Relationships, rs)
+			re.edits = append(re.edits, rs)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeLock)) {
 			if res == nil {
@@ -357,6 +388,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.Locks = []*RECmdLock{}
 			}
 			re.Locks = append(re.Locks, lk)
+			re.edits = append(re.edits, lk)
 			commandNum--
 		} else if p.CurTokenIs(string(RETypeUnlock)) {
 			if res == nil {
@@ -370,6 +402,7 @@ func (p *DataReferenceEditsParser) ParseToken() []*ReferenceEdit {
 				re.Unlocks = []*RECmdUnlock{}
 			}
 			re.Unlocks = append(re.Unlocks, ulk)
+			re.edits = append(re.edits, ulk)
 			commandNum--
 		}
 
@@ -401,6 +434,9 @@ func (p *DataReferenceEditsParser) PeekTokenIs(t string) bool {
 }
 
 func (p *DataReferenceEditsParser) PeekTokenIsNumber() bool {
+	if p.PeekToken == nil {
+		return false
+	}
 	if _, err := strconv.Atoi(*p.PeekToken); err == nil {
 		return true
 	}
@@ -428,7 +464,10 @@ func NewDataReferenceEditsParser(token *[]string, start int) *DataReferenceEdits
 }
 
 func MakeDataReferenceEdits(token *[]string, start int) ([]AttrValue, int, error) {
-	referenceNode := (*token)[start]
+	referenceNode, err := getToken(token, start)
+	if err != nil {
+		return nil, 0, err
+	}
 	re := AttrDataReferenceEdits{
 		TopReferenceNode: strings.Trim(referenceNode, "\""),
 		ReferenceEdits:   []*ReferenceEdit{},
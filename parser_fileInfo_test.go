@@ -8,7 +8,10 @@ func TestMakeFileInfo(t *testing.T) {
 	c := &CmdBuilder{}
 	fileInfoLine := `fileInfo "fileInfoName" "fileInfoValue";`
 	c.Append(fileInfoLine)
-	fi := ParseFileInfo(c.Parse())
+	fi, err := ParseFileInfo(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
 	msg := `got FileInfoCmd %v "%v", wont "%v"`
 	if fi.Name != "fileInfoName" {
 		t.Errorf(msg, "Name", fi.Name, "fileInfoName")
@@ -1,6 +1,7 @@
 package mayaascii
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -8,7 +9,10 @@ func TestMakeFile_ReferenceDepthInfo(t *testing.T) {
 	c := &CmdBuilder{}
 	fileLine := `file -rdi 1 -ns "baseA" -rfn "baseARN" -op "v=0;" -typ "mayaAscii" "C:/baseA.ma";`
 	c.Append(fileLine)
-	f := ParseFile(c.Parse())
+	f, err := ParseFile(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
 	msg := `got FileCmd %v "%v", wont "%v"`
 	if f.ReferenceDepthInfo != 1 {
 		t.Errorf(msg, "ReferenceDepthInfo", f.ReferenceDepthInfo, 1)
@@ -37,7 +41,10 @@ func TestMakeFile_Reference(t *testing.T) {
 	c := &CmdBuilder{}
 	fileLine := `file -r -ns "baseA" -dr 1 -rfn "baseARN" -op "v=0;" -typ "mayaAscii" "C:/baseA.ma";`
 	c.Append(fileLine)
-	f := ParseFile(c.Parse())
+	f, err := ParseFile(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
 	msg := `got FileCmd %v "%v", wont "%v"`
 	if f.Reference != true {
 		t.Errorf(msg, "Reference", f.Reference, true)
@@ -64,3 +71,84 @@ func TestMakeFile_Reference(t *testing.T) {
 		t.Errorf(msg, "f.String()", f.String(), fileLine)
 	}
 }
+
+func TestMakeFile_Flags(t *testing.T) {
+	c := &CmdBuilder{}
+	fileLine := `file -r -ns "baseA" -rpr "pre" -shd "displayLayers" -shd "shadingNetworks" -gl -mnc 1 -lrd "topOnly" -sns "old" "new" -iv -pr -rfn "baseARN" -op "v=0;p=17;VERS" -typ "mayaAscii" "C:/baseA.ma";`
+	c.Append(fileLine)
+	f, err := ParseFile(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := File{fileCmd: f}
+	p, _ := file.GetOptionValue("p")
+	_, hasVers := file.GetOptionValue("VERS")
+	for _, d := range []stringTestData{
+		{"RenamingPrefix", *f.RenamingPrefix, "pre"},
+		{"SharedNodes", strings.Join(f.SharedNodes, ","), "displayLayers,shadingNetworks"},
+		{"LoadReferenceDepth", f.LoadReferenceDepth, "topOnly"},
+		{"SwapNamespaces", f.SwapNamespaces[0].From + ">" + f.SwapNamespaces[0].To, "old>new"},
+		{"GetOptionValue(p)", p, "17"},
+		{"f.String()", f.String(), strings.Replace(fileLine, `" "C:`, "\" \n\t\t\"C:", 1)},
+	} {
+		stringTester(d, t)
+	}
+	for _, d := range []boolTestData{
+		{"DeferReference", f.DeferReference, false},
+		{"GroupLocator", f.GroupLocator, true},
+		{"MergeNamespacesOnClash", *f.MergeNamespacesOnClash, true},
+		{"IgnoreVersion", f.IgnoreVersion, true},
+		{"PreserveReferences", f.PreserveReferences, true},
+		{"has VERS", hasVers, true},
+		{"IsLoaded", file.IsLoaded(), true},
+		{"IsReferenceDepthInfo", file.IsReferenceDepthInfo(), false},
+	} {
+		boolTester(d, t)
+	}
+}
+
+func TestMakeFile_Errors(t *testing.T) {
+	for _, line := range []string{
+		`file -r -ns "a" -dr maybe -rfn "aRN" "C:/a.ma";`,
+		`file -rdi x -ns "a" "C:/a.ma";`,
+		`file -r -ns "C:/a.ma";`,
+	} {
+		c := &CmdBuilder{}
+		c.Append(line)
+		f, err := ParseFile(c.Parse())
+		if err == nil {
+			t.Errorf("got nil, wont error of %s", line)
+		}
+		if f == nil || f.Path != "C:/a.ma" {
+			t.Errorf("got %v, wont FileCmd of %s", f, line)
+		}
+	}
+}
+
+func TestMakeFile_UnknownFlags(t *testing.T) {
+	c := &CmdBuilder{}
+	c.Append(`file -r -ns "a" -new 1 -newer -rfn "aRN" -typ "mayaAscii" "C:/a.ma";`)
+	f, err := ParseFile(c.Parse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []stringTestData{
+		{"UnknownFlags", strings.Join(f.UnknownFlags, " "), "-new 1 -newer"},
+		{"ReferenceNode", f.ReferenceNode, "aRN"},
+		{"f.String()", f.String(),
+			`file -r -ns "a" -new 1 -newer -rfn "aRN" -op "" -typ "mayaAscii" "C:/a.ma";`},
+	} {
+		stringTester(d, t)
+	}
+
+	mo, err := Unmarshal(strings.NewReader(`//Maya ASCII 2030 scene
+file -rdi 1 -ns "a" -new 1 -rfn "aRN" -typ "mayaAscii" "C:/a.ma";
+file -r -ns "a" -new 1 -rfn "aRN" -typ "mayaAscii" "C:/a.ma";
+createNode transform -n "b";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len(Files)", len(mo.Files), 2}, t)
+	intTester(intTestData{"len(Nodes)", len(mo.Nodes), 1}, t)
+}
@@ -0,0 +1,72 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_Malformed(t *testing.T) {
+	for _, raw := range []string{
+		"requires;",
+		"requires maya;",
+		"fileInfo;",
+		"fileInfo \"a\";",
+		"workspace -fr;",
+		"workspace -x \"a\" \"b\";",
+		"createNode;",
+		"createNode transform -n;",
+		"connectAttr \"a\" \"b\";",
+		"connectAttr \"a.t\" \"b.t\" -l;",
+		"addAttr -sn;",
+		"addAttr -ln \"a\" -at;",
+		"setAttr;",
+		"setAttr -s;",
+		"setAttr -s -1 \".a\";",
+		"setAttr \".a\" -type;",
+		"setAttr \".a\" -type \"double3\" 1 2;",
+		"setAttr \".a\" -type \"Int32Array\" 3 1;",
+		"setAttr \".a\" -type \"Int32Array\" -3;",
+		"setAttr \".a\" -type \"stringArray\" 2 \"a\";",
+		"setAttr \".a\" -type \"matrix\" 1 0 0;",
+		"setAttr \".a\" -type \"nurbsCurve\" 1 3 0;",
+		"setAttr \".a\" -type \"nurbsSurface\" 1 1 0 0;",
+		"setAttr \".a\" -type \"polyFaces\" mu 0 3 0 1 2;",
+		"setAttr \".a\" -type \"polyFaces\" f 3 0 1;",
+		"setAttr \".a\" -type \"dataPolyComponent\" Index_Data;",
+		"setAttr \".a\" -type \"lattice\" 2 2;",
+		"setAttr \".a\" -type \"dataReferenceEdits\" \"aRN\" \"\" 5;",
+		"setAttr \".a\" -type \"dataReferenceEdits\" \"aRN\" \"\" \"aRN\" 0;",
+		"setAttr \".a\" -type \"dataReferenceEdits\" \"aRN\" \"\" \"aRN\" 3;",
+	} {
+		c := NewCmd(raw)
+		ParseRequires(c)
+		ParseFileInfo(c)
+		ParseWorkspace(c)
+		ParseCreateNode(c)
+		ParseConnectAttr(c)
+		ParseAddAttr(c)
+		ParseSetAttr(c, nil)
+	}
+}
+
+func TestUnmarshal_Malformed(t *testing.T) {
+	ma := `createNode mesh -n "m";
+	setAttr -s 2 ".vt[0:1]" 0 0;
+	setAttr ".fc[0]" -type "polyFaces" f 3;
+createNode reference -n "aRN";
+	setAttr ".ed" -type "dataReferenceEdits" "aRN" "" "aRN" 2;
+connectAttr "m" "aRN";
+`
+	if _, err := Unmarshal(strings.NewReader(ma)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalLazy(strings.NewReader(ma)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ParseSetAttr(NewCmd("setAttr \".a\" -type \"double3\" 1 2;"), nil)
+	boolTester(boolTestData{
+		title: "ParseSetAttr short double3 error",
+		value: err != nil,
+		wont:  true,
+	}, t)
+}
@@ -8,7 +8,10 @@ func TestMakeRequires_Min(t *testing.T) {
 	cb := &CmdBuilder{}
 	cb.Append(`requires maya "2016";`)
 	c := cb.Parse()
-	r := ParseRequires(c)
+	r, err := ParseRequires(c)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if r.PluginName != "maya" {
 		t.Fatalf("got %v, wont %v", r.PluginName, "maya")
 	}
@@ -28,7 +31,10 @@ func TestMakeRequires_Max(t *testing.T) {
 	cb.Append(`requires -nodeType "typeName1"
 		-dataType "typeName2" "pluginName" "version";`)
 	c := cb.Parse()
-	r := ParseRequires(c)
+	r, err := ParseRequires(c)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if r.PluginName != "pluginName" {
 		t.Fatalf("got %v, wont %v", r.PluginName, "pluginName")
 	}
@@ -8,7 +8,10 @@ func TestMakeWorkspace(t *testing.T) {
 	cb := &CmdBuilder{}
 	cb.Append(`workspace -fr "sourceImages" "sourceimages";`)
 	c := cb.Parse()
-	w := ParseWorkspace(c)
+	w, err := ParseWorkspace(c)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if w.FileRule != "sourceImages" {
 		t.Errorf("got %v, wont %v", w.FileRule, "sourceImages")
 	}
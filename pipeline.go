@@ -0,0 +1,234 @@
+package mayaascii
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// cmdBatchSize is the number of the commands tokenized by a worker at once.
+const cmdBatchSize = 1024
+
+type cmdBatch struct {
+	builders []*CmdBuilder
+	cmds     []*Cmd
+	err      error
+}
+
+// scanCmds splits the lines of reader into the commands and tokenizes
+// them with workers goroutines. The commands are returned in the order of
+// the file. The last command without ";" is dropped. l checks the limits
+// of the commands and pr reports the progress, and both may be nil. The
+// setAttr commands of lazy have Raw without the tokens.
+func scanCmds(ctx context.Context, reader io.Reader, workers int, l *limiter, pr *progress, lazy bool) ([]*Cmd, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	queue := make(chan *cmdBatch, workers)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range queue {
+				if ctx.Err() != nil {
+					b.builders = nil
+					continue
+				}
+				b.cmds = make([]*Cmd, len(b.builders))
+				for i, cb := range b.builders {
+					if lazy && l == nil {
+						if c, ok := cb.parseLazySetAttr(); ok {
+							b.cmds[i] = c
+							continue
+						}
+					}
+					b.cmds[i] = cb.Parse()
+					if err := l.checkSetAttr(b.cmds[i]); err != nil && b.err == nil {
+						b.err = err
+					}
+					if lazy && b.cmds[i].Type == TypeSetAttr {
+						// The limits are checked with the tokens.
+						b.cmds[i].Token = nil
+					}
+				}
+				b.builders = nil
+			}
+		}()
+	}
+
+	var batches []*cmdBatch
+	batch := &cmdBatch{}
+	flush := func() {
+		batches = append(batches, batch)
+		queue <- batch
+		batch = &cmdBatch{}
+		pr.report()
+	}
+	lr := l.newLineReader(bufio.NewReader(pr.reader(reader)))
+	cmdBuilder := &CmdBuilder{}
+	var err error
+	for {
+		if err = checkContext(ctx); err != nil {
+			break
+		}
+		var line string
+		line, err = lr.readLine()
+		if err != nil {
+			break
+		}
+		lineNo := lr.lineNo
+		if !cmdBuilder.isInString() && TypeLineComment.HasPrefix(line) {
+			lineCommentBuilder := &CmdBuilder{lineNo: lineNo - 1}
+			if err = l.appendLine(lineCommentBuilder, line, lineNo); err != nil {
+				break
+			}
+			batch.builders = append(batch.builders, lineCommentBuilder)
+		} else {
+			if err = l.appendLine(cmdBuilder, line, lineNo); err != nil {
+				break
+			}
+			cmdBuilder.lineNo = lineNo
+			if cmdBuilder.IsCmdEOF() {
+				if err = l.countNode(cmdBuilder); err != nil {
+					break
+				}
+				batch.builders = append(batch.builders, cmdBuilder)
+				cmdBuilder = &CmdBuilder{}
+			}
+		}
+		if len(batch.builders) == cmdBatchSize {
+			flush()
+		}
+	}
+	if len(batch.builders) != 0 {
+		flush()
+	}
+	close(queue)
+	wg.Wait()
+	if err != io.EOF {
+		return nil, err
+	}
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	size := 0
+	for _, b := range batches {
+		if b.err != nil {
+			return nil, b.err
+		}
+		size += len(b.cmds)
+	}
+	cmds := make([]*Cmd, 0, size)
+	for _, b := range batches {
+		cmds = append(cmds, b.cmds...)
+	}
+	return cmds, nil
+}
+
+// parsedAttr is an addAttr or setAttr command parsed ahead by parseAttrs.
+type parsedAttr struct {
+	addAttr *AddAttrCmd
+	setAttr *SetAttrCmd
+	err     error
+	ok      bool
+}
+
+// getAttrRuns returns the indexes of the addAttr and setAttr commands that
+// ParseCmds parses with a node or a select. Each run is parsed in order,
+// because a setAttr inherits the values of the previous setAttr of the
+// same attribute like ".vt[500:999]" after ".vt[0:499]".
+func getAttrRuns(cmds []*Cmd) [][]int {
+	var runs [][]int
+	for i := 0; i < len(cmds); {
+		t := cmds[i].Type
+		i++
+		if t != TypeCreateNode && t != TypeSelect {
+			continue
+		}
+		if t == TypeCreateNode && i < len(cmds) && cmds[i].Type == TypeRename {
+			i++
+		}
+		for ; i < len(cmds) && cmds[i].Type == TypeAddAttr; i++ {
+			runs = append(runs, []int{i})
+		}
+		beforeName := ""
+		for start := i; i < len(cmds) && cmds[i].Type == TypeSetAttr; i++ {
+			_, attrName := getAttrNameFromSetAttr(&cmds[i].Token)
+			if i == start || !isSameAttr(beforeName, attrName) {
+				runs = append(runs, []int{})
+			}
+			runs[len(runs)-1] = append(runs[len(runs)-1], i)
+			beforeName = attrName
+		}
+	}
+	return runs
+}
+
+// parseAttrs parses the values of the addAttr and setAttr commands with
+// workers goroutines before ParseCmds builds the nodes.
+func (p *Parser) parseAttrs(workers int) {
+	runs := getAttrRuns(p.cmds)
+	p.parsed = make([]parsedAttr, len(p.cmds))
+	next := int64(-1)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				r := int(atomic.AddInt64(&next, 1))
+				if r >= len(runs) || p.ctx.Err() != nil {
+					return
+				}
+				var beforeSetAttr *SetAttrCmd
+				for _, i := range runs[r] {
+					c := p.cmds[i]
+					if c.Type == TypeSetAttr && p.lazy {
+						// UnmarshalLazy decodes it on first access.
+						break
+					}
+					result := &p.parsed[i]
+					result.ok = true
+					if c.Type == TypeAddAttr {
+						result.addAttr, result.err = ParseAddAttr(c)
+						continue
+					}
+					result.setAttr, result.err = ParseSetAttr(c, beforeSetAttr)
+					if result.err != nil {
+						// ParseCmds stops at the error.
+						break
+					}
+					beforeSetAttr = result.setAttr
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// getParsedAttr returns the result of parseAttrs for CurCmd, or nil.
+func (p *Parser) getParsedAttr() *parsedAttr {
+	i := p.cur - 1
+	if i < 0 || len(p.parsed) <= i || !p.parsed[i].ok {
+		return nil
+	}
+	return &p.parsed[i]
+}
+
+func (p *Parser) parseAddAttr() (*AddAttrCmd, error) {
+	if r := p.getParsedAttr(); r != nil {
+		return r.addAttr, r.err
+	}
+	return ParseAddAttr(p.CurCmd)
+}
+
+func (p *Parser) parseSetAttr(beforeSetAttr *SetAttrCmd) (*SetAttrCmd, error) {
+	if r := p.getParsedAttr(); r != nil {
+		return r.setAttr, r.err
+	}
+	return ParseSetAttr(p.CurCmd, beforeSetAttr)
+}
@@ -0,0 +1,79 @@
+package mayaascii
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func getPipelineTestMa(nodes int) string {
+	var b strings.Builder
+	b.WriteString("//Maya ASCII 2019 scene\n")
+	b.WriteString("requires maya \"2019\";\n")
+	for i := 0; i < nodes; i++ {
+		fmt.Fprintf(&b, "createNode transform -n \"pCube%d\";\n", i)
+		b.WriteString("\tsetAttr \".t\" -type \"double3\" 1 2 3 ;\n")
+		fmt.Fprintf(&b, "createNode mesh -n \"pCubeShape%d\" -p \"pCube%d\";\n", i, i)
+		b.WriteString("\taddAttr -ci true -sn \"note\" -ln \"note\" -dt \"string\";\n")
+		b.WriteString("\tsetAttr -k off \".v\";\n")
+		b.WriteString("\tsetAttr -s 8 \".vt[0:3]\" -0.5 -0.5 0.5 0.5 -0.5 0.5\n")
+		b.WriteString("\t\t-0.5 0.5 0.5 0.5 0.5 0.5;\n")
+		b.WriteString("\tsetAttr \".vt[4:7]\" -0.5 0.5 -0.5 0.5 0.5 -0.5 -0.5 -0.5 -0.5 0.5 -0.5 -0.5;\n")
+		b.WriteString("\tsetAttr -s 2 \".fc[0:1]\" -type \"polyFaces\" \n")
+		b.WriteString("\t\tf 4 0 1 2 3\n")
+		b.WriteString("\t\tf 4 4 5 6 7;\n")
+		b.WriteString("\tsetAttr \".note\" -type \"string\" \"hello\";\n")
+		b.WriteString("// comment\n")
+		fmt.Fprintf(&b, "connectAttr \"pCubeShape%d.w\" \"pCube%d.v\";\n", i, i)
+	}
+	b.WriteString("select -ne :time1;\n")
+	b.WriteString("\tsetAttr \".o\" 1;\n")
+	return b.String()
+}
+
+func TestObject_unmarshal(t *testing.T) {
+	ma := getPipelineTestMa(300)
+	sequential := &Object{Nodes: map[string]*Node{}, connections: NewConnections()}
+	if err := sequential.unmarshal(context.Background(), strings.NewReader(ma), UnmarshalOptions{Workers: 1}); err != nil {
+		t.Fatal(err)
+	}
+	parallel := &Object{Nodes: map[string]*Node{}, connections: NewConnections()}
+	if err := parallel.unmarshal(context.Background(), strings.NewReader(ma), UnmarshalOptions{Workers: 8}); err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len(Nodes)", len(parallel.Nodes), 600}, t)
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Errorf("got the parallel result different from the sequential result")
+	}
+
+	shape, err := parallel.GetNode("pCubeShape299")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{".vt[4:7] values", len(shape.GetAttr(".vt[4:7]").GetAttrValue()), 24}, t)
+	intTester(intTestData{".vt[4:7] LineNo",
+		int(shape.GetAttr(".vt[4:7]").getCmd().LineNo), 2 + 299*14 + 8}, t)
+}
+
+func benchmarkUnmarshal(b *testing.B, workers int) {
+	ma := getPipelineTestMa(5000)
+	b.SetBytes(int64(len(ma)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := &Object{Nodes: map[string]*Node{}, connections: NewConnections()}
+		if err := o.unmarshal(context.Background(), strings.NewReader(ma), UnmarshalOptions{Workers: workers}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal_Sequential(b *testing.B) {
+	benchmarkUnmarshal(b, 1)
+}
+
+func BenchmarkUnmarshal_Parallel(b *testing.B) {
+	benchmarkUnmarshal(b, runtime.GOMAXPROCS(0))
+}
@@ -0,0 +1,75 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SceneConfigurationScriptNode is the scriptNode that has the
+// playbackOptions of the scene.
+const SceneConfigurationScriptNode = "sceneConfigurationScriptNode"
+
+// FrameRange is the playbackOptions of the scene.
+type FrameRange struct {
+	// Min and Max is the playback range, "-min" and "-max".
+	Min float64
+	Max float64
+	// AnimationStart and AnimationEnd is the animation range, "-ast" and
+	// "-aet".
+	AnimationStart float64
+	AnimationEnd   float64
+	// By is the increment of the playback, "-by", or 0.
+	By float64
+}
+
+// GetFrameRange returns the playbackOptions of the
+// sceneConfigurationScriptNode.
+func (o *Object) GetFrameRange() (*FrameRange, error) {
+	s, err := o.GetScriptNode(SceneConfigurationScriptNode)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlaybackOptions(s.Before)
+}
+
+// ParsePlaybackOptions parses the first playbackOptions of the MEL script
+// like "playbackOptions -min 1 -max 120 -ast 1 -aet 200 ".
+func ParsePlaybackOptions(script string) (*FrameRange, error) {
+	for _, statement := range strings.Split(script, ";") {
+		fields := strings.Fields(statement)
+		if len(fields) == 0 || fields[0] != "playbackOptions" {
+			continue
+		}
+		fr := &FrameRange{}
+		for i := 1; i < len(fields); i++ {
+			var v *float64
+			switch fields[i] {
+			case "-min", "-minTime":
+				v = &fr.Min
+			case "-max", "-maxTime":
+				v = &fr.Max
+			case "-ast", "-animationStartTime":
+				v = &fr.AnimationStart
+			case "-aet", "-animationEndTime":
+				v = &fr.AnimationEnd
+			case "-by":
+				v = &fr.By
+			default:
+				continue
+			}
+			if len(fields) <= i+1 {
+				return nil, errors.New(fmt.Sprintf("%s has no value. %s", fields[i], statement))
+			}
+			f, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("%s is not a number. %s", fields[i+1], statement))
+			}
+			*v = f
+			i++
+		}
+		return fr, nil
+	}
+	return nil, errors.New("playbackOptions was not found")
+}
@@ -0,0 +1,57 @@
+package mayaascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObject_GetFrameRange(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode script -n "sceneConfigurationScriptNode";
+	setAttr ".b" -type "string" "playbackOptions -min 1001 -max 1120 -ast 991 -aet 1130 -by 0.5 ";
+	setAttr ".st" 6;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := o.GetFrameRange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"Min", int(fr.Min), 1001}, t)
+	intTester(intTestData{"Max", int(fr.Max), 1120}, t)
+	intTester(intTestData{"AnimationStart", int(fr.AnimationStart), 991}, t)
+	intTester(intTestData{"AnimationEnd", int(fr.AnimationEnd), 1130}, t)
+	boolTester(boolTestData{"By", fr.By == 0.5, true}, t)
+
+	o, err = Unmarshal(strings.NewReader(getTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fr, err = o.GetFrameRange(); err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"test.ma Max", int(fr.Max), 120}, t)
+	intTester(intTestData{"test.ma AnimationEnd", int(fr.AnimationEnd), 200}, t)
+
+	o, err = Unmarshal(strings.NewReader("createNode transform -n \"a\";\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.GetFrameRange(); err == nil {
+		t.Errorf("GetFrameRange() without sceneConfigurationScriptNode got nil error")
+	}
+}
+
+func TestParsePlaybackOptions(t *testing.T) {
+	fr, err := ParsePlaybackOptions("currentUnit -t film; playbackOptions -minTime 1 -maxTime 24;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"Min", int(fr.Min), 1}, t)
+	intTester(intTestData{"Max", int(fr.Max), 24}, t)
+	for _, script := range []string{"", "playbackOptions -min", "playbackOptions -max a"} {
+		if _, err := ParsePlaybackOptions(script); err == nil {
+			t.Errorf("ParsePlaybackOptions(%q) got nil error", script)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mayaascii
+
+import (
+	"context"
+	"io"
+)
+
+// Progress is the progress of UnmarshalContext. Unmarshal reads and
+// tokenizes the whole file first, then builds the nodes, so BytesRead
+// grows first and then Cmds grows to TotalCmds.
+type Progress struct {
+	BytesRead int64
+	// Cmds is the commands parsed into the Object.
+	Cmds int
+	// TotalCmds is the commands of the file. It is 0 while reading.
+	TotalCmds int
+	// Nodes is the nodes created.
+	Nodes int
+}
+
+// progressInterval is the number of the commands between the reports.
+const progressInterval = 1024
+
+// progress reports Progress to UnmarshalOptions.Progress. A nil progress
+// reports nothing.
+type progress struct {
+	fn func(Progress)
+	p  Progress
+}
+
+func newProgress(fn func(Progress)) *progress {
+	if fn == nil {
+		return nil
+	}
+	return &progress{fn: fn}
+}
+
+func (pr *progress) report() {
+	if pr != nil {
+		pr.fn(pr.p)
+	}
+}
+
+// reader returns reader that counts BytesRead.
+func (pr *progress) reader(reader io.Reader) io.Reader {
+	if pr == nil {
+		return reader
+	}
+	return &countingReader{r: reader, n: &pr.p.BytesRead}
+}
+
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (cr *countingReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	*cr.n += int64(n)
+	return n, err
+}
+
+// checkContext returns the error of ctx if it is done.
+func checkContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package mayaascii
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalContext_Progress(t *testing.T) {
+	ma := getPipelineTestMa(1000)
+	var reports []Progress
+	o, err := UnmarshalContext(context.Background(), strings.NewReader(ma), UnmarshalOptions{
+		Progress: func(p Progress) {
+			reports = append(reports, p)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"reports", 2 < len(reports), true}, t)
+	for i := 1; i < len(reports); i++ {
+		before, p := reports[i-1], reports[i]
+		if p.BytesRead < before.BytesRead || p.Cmds < before.Cmds || p.Nodes < before.Nodes {
+			t.Errorf("progress %d %v goes back from %v", i, p, before)
+		}
+	}
+	last := reports[len(reports)-1]
+	intTester(intTestData{"BytesRead", int(last.BytesRead), len(ma)}, t)
+	intTester(intTestData{"TotalCmds", last.TotalCmds, len(o.cmds)}, t)
+	intTester(intTestData{"Cmds", last.Cmds, len(o.cmds)}, t)
+	intTester(intTestData{"Nodes", last.Nodes, 2000}, t)
+}
+
+func TestUnmarshalContext_Cancel(t *testing.T) {
+	ma := getPipelineTestMa(1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := UnmarshalContext(ctx, strings.NewReader(ma), UnmarshalOptions{})
+	boolTester(boolTestData{"cancelled before", err == context.Canceled, true}, t)
+
+	for _, title := range []string{"reading", "parsing"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var last Progress
+		o := &Object{Nodes: map[string]*Node{}, connections: NewConnections()}
+		err := o.UnmarshalContext(ctx, strings.NewReader(ma), UnmarshalOptions{
+			Progress: func(p Progress) {
+				last = p
+				if (title == "reading" && p.TotalCmds == 0) || 0 < p.Cmds {
+					cancel()
+				}
+			},
+		})
+		boolTester(boolTestData{title + " cancelled", err == context.Canceled, true}, t)
+		boolTester(boolTestData{title + " stopped", len(o.Nodes) < 2000, true}, t)
+		boolTester(boolTestData{title + " read", last.BytesRead < int64(len(ma)), title == "reading"}, t)
+	}
+}
@@ -0,0 +1,207 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrReferenceNotFound        = errors.New("referenced file was not found")
+	ErrReferenceCycle           = errors.New("reference cycle was detected")
+	ErrUnsupportedReferenceType = errors.New("referenced file is not mayaAscii")
+)
+
+// ReferenceResolver maps File.GetPath() to the content of the file.
+type ReferenceResolver interface {
+	// Resolve returns the reader of the referenced file and the resolved
+	// path. The resolved path identifies the file to detect cycles.
+	Resolve(path string) (io.ReadCloser, string, error)
+}
+
+// DirMap is a dirmap rule. A path that starts with From is mapped to To.
+type DirMap struct {
+	From string
+	To   string
+}
+
+// FileReferenceResolver resolves references on the filesystem.
+// The path is resolved in this order:
+//  1. environment variables ($VAR, ${VAR} and %VAR%) are expanded
+//  2. the first matched DirMaps rule is applied
+//  3. the path itself, then SearchPaths joined with the path and with
+//     the file name are tried
+type FileReferenceResolver struct {
+	SearchPaths []string
+	DirMaps     []DirMap
+	// Getenv is used to expand environment variables. os.Getenv if nil.
+	Getenv func(key string) string
+}
+
+// copyNumberRegexp matches "{1}" that Maya appends to a path referenced twice.
+var copyNumberRegexp = regexp.MustCompile(`\{\d+\}$`)
+
+var windowsEnvRegexp = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+func (r *FileReferenceResolver) getenv(key string) string {
+	if r.Getenv != nil {
+		return r.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// Candidates returns the paths that Resolve tries, in order.
+func (r *FileReferenceResolver) Candidates(p string) []string {
+	p = copyNumberRegexp.ReplaceAllString(p, "")
+	p = windowsEnvRegexp.ReplaceAllStringFunc(p, func(s string) string {
+		return r.getenv(s[1 : len(s)-1])
+	})
+	p = os.Expand(p, r.getenv)
+	p = strings.Replace(p, "\\", "/", -1)
+	for _, dm := range r.DirMaps {
+		if mapped, ok := replacePathPrefix(p, dm.From, dm.To); ok {
+			p = mapped
+			break
+		}
+	}
+	candidates := []string{p}
+	for _, sp := range r.SearchPaths {
+		sp = strings.Replace(sp, "\\", "/", -1)
+		if !path.IsAbs(p) && !isWindowsAbs(p) {
+			candidates = append(candidates, path.Join(sp, p))
+		}
+		candidates = append(candidates, path.Join(sp, path.Base(p)))
+	}
+	return candidates
+}
+
+func isWindowsAbs(p string) bool {
+	return len(p) >= 2 && p[1] == ':'
+}
+
+func (r *FileReferenceResolver) Resolve(p string) (io.ReadCloser, string, error) {
+	for _, c := range r.Candidates(p) {
+		info, err := os.Stat(c)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		f, err := os.Open(c)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, c, nil
+	}
+	return nil, "", ErrReferenceNotFound
+}
+
+// ReferenceError is an error of a reference that LoadReferences could not
+// load.
+type ReferenceError struct {
+	File *File
+	Err  error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s (%s): %s",
+		e.File.GetReferenceNode(), e.File.GetPath(), e.Err.Error())
+}
+
+func (e *ReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// ReferenceErrors is the errors of LoadReferences.
+type ReferenceErrors []*ReferenceError
+
+func (es ReferenceErrors) Error() string {
+	var s []string
+	for _, e := range es {
+		s = append(s, e.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+// LoadReferences parses the files of the loaded references ("file -r")
+// into File.Object, recursively. depth limits the depth of the nested
+// references, 0 or less means no limit. Deferred (unloaded) references
+// are not loaded. The references that can't be loaded are returned as
+// ReferenceErrors after every other reference was loaded. A reference to
+// the file of Open is a cycle; use LoadReferencesFrom for an Object of
+// Unmarshal.
+func (o *Object) LoadReferences(resolver ReferenceResolver, depth int) error {
+	return o.LoadReferencesFrom(o.path, resolver, depth)
+}
+
+// LoadReferencesFrom is LoadReferences of the scene at root, the path that
+// the references back to it are cycles. An empty root is not a file.
+func (o *Object) LoadReferencesFrom(root string, resolver ReferenceResolver, depth int) error {
+	var stack []string
+	if root != "" {
+		stack = append(stack, root)
+	}
+	errs := o.loadReferences(resolver, depth, stack)
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// isSamePath reports whether the paths a and b are the same file.
+func isSamePath(a, b string) bool {
+	abs := func(p string) string {
+		p = filepath.FromSlash(p)
+		if ap, err := filepath.Abs(p); err == nil {
+			return ap
+		}
+		return filepath.Clean(p)
+	}
+	return a == b || abs(a) == abs(b)
+}
+
+func (o *Object) loadReferences(resolver ReferenceResolver, depth int, stack []string) ReferenceErrors {
+	var errs ReferenceErrors
+	for _, f := range o.Files {
+		if !f.IsLoaded() {
+			continue
+		}
+		if f.GetType() != "" && f.GetType() != "mayaAscii" {
+			errs = append(errs, &ReferenceError{File: f, Err: ErrUnsupportedReferenceType})
+			continue
+		}
+		reader, resolved, err := resolver.Resolve(f.GetPath())
+		if err != nil {
+			errs = append(errs, &ReferenceError{File: f, Err: err})
+			continue
+		}
+		isCycle := false
+		for _, s := range stack {
+			if isSamePath(s, resolved) {
+				isCycle = true
+			}
+		}
+		if isCycle {
+			reader.Close()
+			errs = append(errs, &ReferenceError{File: f, Err: ErrReferenceCycle})
+			continue
+		}
+		child, err := Unmarshal(reader)
+		reader.Close()
+		if err != nil {
+			errs = append(errs, &ReferenceError{File: f, Err: err})
+			continue
+		}
+		child.path = resolved
+		f.Object = child
+		if depth == 1 {
+			continue
+		}
+		childStack := append(append([]string{}, stack...), resolved)
+		errs = append(errs, child.loadReferences(resolver, depth-1, childStack)...)
+	}
+	return errs
+}
@@ -0,0 +1,185 @@
+package mayaascii
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestMa(t *testing.T, dir, name, body string) {
+	err := ioutil.WriteFile(filepath.Join(dir, name), []byte(body), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileReferenceResolver_Candidates(t *testing.T) {
+	r := &FileReferenceResolver{
+		SearchPaths: []string{"/search"},
+		DirMaps:     []DirMap{{From: "C:/projects", To: "/mnt/projects"}},
+		Getenv: func(key string) string {
+			if key == "ASSETS" {
+				return "C:\\projects\\assets"
+			}
+			return ""
+		},
+	}
+	for _, d := range []stringTestData{
+		{"$ASSETS", strings.Join(r.Candidates("$ASSETS/char.ma{1}"), ","),
+			"/mnt/projects/assets/char.ma,/search/char.ma"},
+		{"%ASSETS%", strings.Join(r.Candidates("%ASSETS%/char.ma"), ","),
+			"/mnt/projects/assets/char.ma,/search/char.ma"},
+		{"relative", strings.Join(r.Candidates("scenes/char.ma"), ","),
+			"scenes/char.ma,/search/scenes/char.ma,/search/char.ma"},
+	} {
+		stringTester(d, t)
+	}
+}
+
+func TestObject_LoadReferences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mayaascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestMa(t, dir, "char.ma", `//Maya ASCII 2019 scene
+file -r -ns "loop" -rfn "loopRN" -typ "mayaAscii" "loop.ma";
+createNode transform -n "root";`)
+	writeTestMa(t, dir, "loop.ma", `//Maya ASCII 2019 scene
+file -r -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";
+createNode transform -n "loopRoot";`)
+	writeTestMa(t, dir, "prop.ma", `//Maya ASCII 2019 scene
+createNode transform -n "prop";`)
+
+	mo, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+file -rdi 1 -ns "char" -rfn "charRN" -typ "mayaAscii" "$ASSETS/char.ma";
+file -r -ns "char" -rfn "charRN" -typ "mayaAscii" "$ASSETS/char.ma";
+file -r -ns "prop" -rfn "propRN" -typ "mayaAscii" "C:/old/prop.ma";
+file -r -ns "missing" -rfn "missingRN" -typ "mayaAscii" "C:/old/missing.ma";
+file -r -ns "deferred" -dr 1 -rfn "deferredRN" -typ "mayaAscii" "C:/old/missing.ma";
+file -r -ns "binary" -rfn "binaryRN" -typ "mayaBinary" "C:/old/binary.mb";
+createNode transform -n "shot";`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := &FileReferenceResolver{
+		SearchPaths: []string{dir},
+		DirMaps:     []DirMap{{From: "C:/old", To: dir}},
+		Getenv: func(key string) string {
+			if key == "ASSETS" {
+				return dir
+			}
+			return ""
+		},
+	}
+	err = mo.LoadReferences(resolver, 0)
+	var errs ReferenceErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, wont ReferenceErrors", err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors, wont 3: %v", len(errs), errs)
+	}
+	for _, d := range []struct {
+		rn  string
+		err error
+	}{
+		{"missingRN", ErrReferenceNotFound},
+		{"binaryRN", ErrUnsupportedReferenceType},
+		{"charRN", ErrReferenceCycle},
+	} {
+		found := false
+		for _, e := range errs {
+			if e.File.GetReferenceNode() == d.rn && errors.Is(e, d.err) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("got %v, wont %s error of %s", errs, d.err, d.rn)
+		}
+	}
+
+	char := mo.Files[1].Object
+	if char == nil {
+		t.Fatal("got nil, wont char.ma Object")
+	}
+	if _, err := char.GetNode("root"); err != nil {
+		t.Error(err)
+	}
+	loop := char.Files[0].Object
+	if loop == nil {
+		t.Fatal("got nil, wont loop.ma Object")
+	}
+	if loop.Files[0].Object != nil {
+		t.Error("got Object, wont nil for the cyclic reference")
+	}
+	if mo.Files[2].Object == nil {
+		t.Error("got nil, wont prop.ma Object")
+	}
+	if mo.Files[0].Object != nil || mo.Files[4].Object != nil {
+		t.Error("got Object, wont nil for -rdi and deferred reference")
+	}
+
+	mo, _ = Unmarshal(strings.NewReader(`file -r -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";`))
+	if err := mo.LoadReferences(&FileReferenceResolver{SearchPaths: []string{dir}}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if mo.Files[0].Object.Files[0].Object != nil {
+		t.Error("got Object, wont nil for depth 1")
+	}
+}
+
+func TestObject_LoadReferences_directCycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mayaascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeTestMa(t, dir, "a.ma", `//Maya ASCII 2019 scene
+file -r -ns "b" -rfn "bRN" -typ "mayaAscii" "b.ma";
+createNode transform -n "aRoot";`)
+	writeTestMa(t, dir, "b.ma", `//Maya ASCII 2019 scene
+file -r -ns "a" -rfn "aRN" -typ "mayaAscii" "a.ma";
+createNode transform -n "bRoot";`)
+	resolver := &FileReferenceResolver{SearchPaths: []string{dir}}
+
+	opened, err := Open(filepath.Join(dir, "a.ma"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshaled, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+file -r -ns "b" -rfn "bRN" -typ "mayaAscii" "b.ma";`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, load := range map[string]func() (*Object, error){
+		"Open": func() (*Object, error) {
+			return opened, opened.LoadReferences(resolver, 0)
+		},
+		"LoadReferencesFrom": func() (*Object, error) {
+			return unmarshaled, unmarshaled.LoadReferencesFrom(
+				filepath.ToSlash(filepath.Join(dir, "a.ma")), resolver, 0)
+		},
+	} {
+		mo, err := load()
+		var errs ReferenceErrors
+		if !errors.As(err, &errs) || len(errs) != 1 ||
+			errs[0].File.GetReferenceNode() != "aRN" || !errors.Is(errs[0], ErrReferenceCycle) {
+			t.Errorf("%s: got %v, wont a cycle error of aRN", title, err)
+			continue
+		}
+		b := mo.Files[0].Object
+		if b == nil {
+			t.Errorf("%s: got nil, wont b.ma Object", title)
+			continue
+		}
+		if b.Files[0].Object != nil {
+			t.Errorf("%s: got Object, wont nil for a.ma parsed again", title)
+		}
+	}
+}
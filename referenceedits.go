@@ -0,0 +1,920 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReferenceEditError is a reference edit that ApplyReferenceEdits could
+// not apply.
+type ReferenceEditError struct {
+	ReferenceNode string
+	Edit          fmt.Stringer
+	Err           error
+}
+
+func (e *ReferenceEditError) Error() string {
+	return fmt.Sprintf("%s: %s: %s",
+		e.ReferenceNode, strings.TrimSpace(e.Edit.String()), e.Err.Error())
+}
+
+func (e *ReferenceEditError) Unwrap() error {
+	return e.Err
+}
+
+// ReferenceEditErrors is the errors of ApplyReferenceEdits.
+type ReferenceEditErrors []*ReferenceEditError
+
+func (es ReferenceEditErrors) Error() string {
+	var s []string
+	for _, e := range es {
+		s = append(s, e.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+// ApplyReferenceEdits turns the Objects loaded by LoadReferences into the
+// effective state of the scene. The nodes of each referenced Object get
+// the namespace of the reference, then the reference edits of the scene
+// are applied to them: setAttr, addAttr, deleteAttr, connectAttr,
+// disconnectAttr, parent, lock and unlock. Relationship edits and the
+// connections of the ".placeHolderList" of reference nodes, which the
+// scene writes as its own connectAttr, are not applied. The edits of the
+// referenced files are applied to their own references first, like Maya
+// does.
+//
+// The edits are applied only once. The edits that can't be applied,
+// because their node, attribute or connection is missing, are returned as
+// ReferenceEditErrors after every other edit was applied.
+func (o *Object) ApplyReferenceEdits() error {
+	errs := o.applyReferenceEdits()
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (o *Object) applyReferenceEdits() ReferenceEditErrors {
+	if o.referenceEditsApplied {
+		return nil
+	}
+	o.referenceEditsApplied = true
+
+	var errs ReferenceEditErrors
+	for _, f := range o.Files {
+		if f.Object == nil {
+			continue
+		}
+		errs = append(errs, f.Object.applyReferenceEdits()...)
+		f.Object.prefixNamespace(f.GetNamespace())
+	}
+
+	for _, a := range o.referenceEditsAttrs() {
+		for _, ed := range a.edits {
+			for _, re := range ed.ReferenceEdits {
+				if re == nil {
+					continue
+				}
+				target := o.findReferenceObject(re.ReferenceNode)
+				if target == nil {
+					// The reference is not loaded.
+					continue
+				}
+				errs = append(errs, o.applyReferenceEdit(target, re)...)
+			}
+		}
+	}
+	return errs
+}
+
+// findReferenceObject returns the loaded Object of the reference node rfn.
+func (o *Object) findReferenceObject(rfn string) *Object {
+	for _, f := range o.Files {
+		if f.Object == nil {
+			continue
+		}
+		if f.GetReferenceNode() == rfn {
+			return f.Object
+		}
+		if found := f.Object.findReferenceObject(rfn); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// prefixNamespace moves the nodes of o and of its loaded references into
+// the namespace ns. Shared nodes and default nodes keep their names.
+func (o *Object) prefixNamespace(ns string) {
+	if ns == "" {
+		return
+	}
+	isPrefixed := func(name string) bool {
+		if n, ok := o.Nodes[name]; ok {
+			return !n.IsShared()
+		}
+		for _, f := range o.Files {
+			if isInNamespace(name, f.getFullNamespace()) {
+				return true
+			}
+		}
+		return false
+	}
+	o.rewriteNodeRefs(func(path string) (string, bool) {
+		comps := strings.Split(path, "|")
+		changed := false
+		for i, comp := range comps {
+			name := strings.TrimPrefix(comp, ":")
+			if name == "" || !isPrefixed(name) {
+				continue
+			}
+			comps[i] = ns + ":" + name
+			changed = true
+		}
+		return strings.Join(comps, "|"), changed
+	})
+	for _, n := range o.sortedNodes() {
+		if !n.IsShared() {
+			o.setNodeName(n, ns+":"+n.GetName())
+		}
+	}
+
+	namespaces := map[string]struct{}{}
+	for name := range o.namespaces {
+		namespaces[ns+":"+name] = struct{}{}
+	}
+	o.namespaces = namespaces
+
+	for _, f := range o.Files {
+		if f.Object != nil {
+			f.Object.prefixNamespace(ns)
+		}
+	}
+}
+
+func (o *Object) applyReferenceEdit(target *Object, re *ReferenceEdit) ReferenceEditErrors {
+	var errs ReferenceEditErrors
+	report := func(edit fmt.Stringer, err error) {
+		if err != nil {
+			if o.failedReferenceEdits == nil {
+				o.failedReferenceEdits = map[fmt.Stringer]struct{}{}
+			}
+			o.failedReferenceEdits[edit] = struct{}{}
+			errs = append(errs, &ReferenceEditError{
+				ReferenceNode: re.ReferenceNode,
+				Edit:          edit,
+				Err:           err,
+			})
+		}
+	}
+	for _, prt := range re.Parents {
+		report(prt, o.applyParentEdit(target, prt))
+	}
+	for _, aa := range re.AddAttrs {
+		report(aa, target.applyAddAttrEdit(aa))
+	}
+	for _, sa := range re.SetAttrs {
+		report(sa, target.applySetAttrEdit(sa))
+	}
+	for _, da := range re.DisconnectAttrs {
+		report(da, o.applyDisconnectAttrEdit(target, da))
+	}
+	for _, da := range re.DeleteAttrs {
+		report(da, target.applyDeleteAttrEdit(da))
+	}
+	for _, ca := range re.ConnectAttrs {
+		report(ca, o.applyConnectAttrEdit(target, ca))
+	}
+	for _, lk := range re.Locks {
+		report(lk, target.applyLockEdit(lk.Node, lk.Attr, true))
+	}
+	for _, ulk := range re.Unlocks {
+		report(ulk, target.applyLockEdit(ulk.Node, ulk.Attr, false))
+	}
+	return errs
+}
+
+// findEditNode returns the node of path in target, or in o when the edit
+// refers to a node of the scene.
+func (o *Object) findEditNode(target *Object, path string) (*Node, error) {
+	if n := target.findNodeByPath(path, true); n != nil {
+		return n, nil
+	}
+	if n := o.findNodeByPath(path, true); n != nil {
+		return n, nil
+	}
+	return nil, errors.New(fmt.Sprintf("%s node was not found", path))
+}
+
+func (o *Object) applyParentEdit(target *Object, prt *RECmdParent) error {
+	child, err := o.findEditNode(target, prt.NodeA)
+	if err != nil {
+		return err
+	}
+	if prt.NodeB == "" || strings.Contains(prt.Arguments, "-w") {
+		child.setParent(nil)
+		return nil
+	}
+	parent, err := o.findEditNode(target, prt.NodeB)
+	if err != nil {
+		return err
+	}
+	child.setParent(parent)
+	return nil
+}
+
+// setParent moves n under parent, or to the world when parent is nil.
+func (n *Node) setParent(parent *Node) {
+	if n.Parent != nil {
+		for i, c := range n.Parent.Children {
+			if c == n {
+				n.Parent.Children = append(n.Parent.Children[:i], n.Parent.Children[i+1:]...)
+				break
+			}
+		}
+	}
+	n.Parent = parent
+	cn := n.createNodeCmd
+	if parent == nil {
+		cn.Cmd.removeFlag("-p")
+		cn.Parent = nil
+		return
+	}
+	parent.Children = append(parent.Children, n)
+	name := parent.GetName()
+	cn.Cmd.setFlag("-p", quote(name))
+	cn.Parent = &name
+}
+
+func (o *Object) applyAddAttrEdit(aa *RECmdAddAttr) error {
+	n := o.findNodeByPath(strings.Trim(aa.Node, "\""), true)
+	if n == nil {
+		return errors.New(fmt.Sprintf("%s node was not found", aa.Node))
+	}
+	c := NewCmd(fmt.Sprintf("\taddAttr -ln %s -sn %s%s;",
+		quote(aa.LongAttr), quote(aa.ShortAttr), unescapeArguments(aa.Arguments)))
+	ad, err := ParseAddAttr(c)
+	if err != nil {
+		return err
+	}
+	// addAttr is inserted after the last addAttr of the node.
+	prev := n.createNodeCmd.Cmd
+	if n.renameCmd != nil {
+		prev = n.renameCmd.Cmd
+	}
+	i := 0
+	for ; i < len(n.Attrs); i++ {
+		if _, ok := n.Attrs[i].attrCmd.(*AddAttrCmd); !ok {
+			break
+		}
+		prev = n.Attrs[i].getCmd()
+	}
+	a := &Attr{Node: n, attrCmd: ad}
+	n.Attrs = append(n.Attrs, nil)
+	copy(n.Attrs[i+1:], n.Attrs[i:])
+	n.Attrs[i] = a
+	o.insertCmdAfter(prev, c)
+	o.bindRequireData(n, c)
+	return nil
+}
+
+func (o *Object) applySetAttrEdit(sa *RECmdSetAttr) error {
+	n := o.findNodeByPath(sa.Node, true)
+	if n == nil {
+		return errors.New(fmt.Sprintf("%s node was not found", sa.Node))
+	}
+	c := NewCmd(fmt.Sprintf("\tsetAttr %s%s;",
+		quote("."+sa.Attr), unescapeArguments(sa.Arguments)))
+	parsed, err := ParseSetAttr(c, nil)
+	if err != nil {
+		return err
+	}
+	o.bindRequireData(n, c)
+	for i := len(n.Attrs) - 1; i >= 0; i-- {
+		old, ok := n.Attrs[i].getSetAttr()
+		if !ok || !n.isSameAttrName(old.AttrName, parsed.AttrName) {
+			continue
+		}
+		// The edit overrides the value of the referenced file, keeping the
+		// attribute name of the file like ".t" of "translate".
+		if old.AttrName != parsed.AttrName {
+			c = NewCmd(fmt.Sprintf("\tsetAttr %s%s;",
+				quote(old.AttrName), unescapeArguments(sa.Arguments)))
+			if parsed, err = ParseSetAttr(c, nil); err != nil {
+				return err
+			}
+			o.bindRequireData(n, c)
+		}
+		if j := o.indexOfCmd(old.Cmd); j != -1 {
+			o.cmds[j] = c
+		}
+		n.Attrs[i].attrCmd = parsed
+		return nil
+	}
+	o.insertCmdAfter(n.lastCmd(), c)
+	n.Attrs = append(n.Attrs, &Attr{Node: n, attrCmd: parsed})
+	return nil
+}
+
+func (o *Object) applyDeleteAttrEdit(da *RECmdDeleteAttr) error {
+	n := o.findNodeByPath(da.Node, true)
+	if n == nil {
+		return errors.New(fmt.Sprintf("%s node was not found", da.Node))
+	}
+	found := false
+	var attrs []*Attr
+	for _, a := range n.Attrs {
+		switch ac := a.attrCmd.(type) {
+		case *AddAttrCmd:
+			if isAddAttrName(ac, da.Attr) {
+				found = true
+				o.removeCmd(ac.Cmd)
+				continue
+			}
+		case *SetAttrCmd:
+			if isSameAttr("."+da.Attr, ac.AttrName) ||
+				strings.HasPrefix(ac.AttrName, "."+da.Attr+".") {
+				o.removeCmd(ac.Cmd)
+				continue
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	if !found {
+		return errors.New(fmt.Sprintf("%s.%s attribute was not found",
+			da.Node, da.Attr))
+	}
+	n.Attrs = attrs
+	return nil
+}
+
+func isAddAttrName(aa *AddAttrCmd, name string) bool {
+	return (aa.LongName != nil && *aa.LongName == name) ||
+		(aa.ShortName != nil && *aa.ShortName == name)
+}
+
+// resolvePlug returns the plug "node.attr" of a plug with a DAG path.
+func (o *Object) resolvePlug(target *Object, plug string) (string, string, error) {
+	path := plug
+	attr := ""
+	// The attribute starts at the first dot after the last DAG separator.
+	sep := strings.LastIndex(plug, "|")
+	if dotIndex := strings.Index(plug[sep+1:], "."); dotIndex != -1 {
+		path = plug[:sep+1+dotIndex]
+		attr = plug[sep+2+dotIndex:]
+	}
+	n, err := o.findEditNode(target, path)
+	if err != nil {
+		return "", "", err
+	}
+	return n.GetName(), attr, nil
+}
+
+// isPlaceHolderPlug reports whether plug is a ".placeHolderList[n]" of a
+// reference node. The scene writes the connections of the placeholders as
+// its own connectAttr, so the edits of them are not applied to target.
+func (o *Object) isPlaceHolderPlug(target *Object, plug string) bool {
+	node, attr, err := o.resolvePlug(target, plug)
+	if err != nil {
+		return false
+	}
+	if i := strings.Index(attr, "["); i != -1 {
+		attr = attr[:i]
+	}
+	if attr != "placeHolderList" && attr != "phl" {
+		return false
+	}
+	n, err := o.findEditNode(target, node)
+	return err == nil && n.GetType() == "reference"
+}
+
+func (o *Object) applyDisconnectAttrEdit(target *Object, da *RECmdDisconnectAttr) error {
+	if o.isPlaceHolderPlug(target, da.SourcePlug) || o.isPlaceHolderPlug(target, da.DistPlug) {
+		return nil
+	}
+	srcNode, srcAttr, err := o.resolvePlug(target, da.SourcePlug)
+	if err != nil {
+		return err
+	}
+	dstNode, dstAttr, err := o.resolvePlug(target, da.DistPlug)
+	if err != nil {
+		return err
+	}
+	for _, owner := range []*Object{target, o} {
+		for i, ca := range owner.connections.source {
+			if ca.SrcNode != srcNode || ca.SrcAttr != srcAttr ||
+				ca.DstNode != dstNode || ca.DstAttr != dstAttr {
+				continue
+			}
+			owner.connections.source = append(
+				owner.connections.source[:i], owner.connections.source[i+1:]...)
+			owner.removeCmd(ca.Cmd)
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("%s -> %s connection was not found",
+		da.SourcePlug, da.DistPlug))
+}
+
+func (o *Object) applyConnectAttrEdit(target *Object, ca *RECmdConnectAttr) error {
+	if o.isPlaceHolderPlug(target, ca.SourcePlug) || o.isPlaceHolderPlug(target, ca.DistPlug) {
+		return nil
+	}
+	srcNode, srcAttr, err := o.resolvePlug(target, ca.SourcePlug)
+	if err != nil {
+		return err
+	}
+	dstNode, dstAttr, err := o.resolvePlug(target, ca.DistPlug)
+	if err != nil {
+		return err
+	}
+	c := NewCmd(fmt.Sprintf("connectAttr %s %s%s;",
+		quote(srcNode+"."+srcAttr), quote(dstNode+"."+dstAttr),
+		unescapeArguments(ca.Arguments)))
+	parsed, err := ParseConnectAttr(c)
+	if err != nil {
+		return err
+	}
+	target.connections.Append(parsed)
+	target.cmds = append(target.cmds, c)
+	return nil
+}
+
+func (o *Object) applyLockEdit(node, attr string, lock bool) error {
+	n := o.findNodeByPath(node, true)
+	if n == nil {
+		return errors.New(fmt.Sprintf("%s node was not found", node))
+	}
+	value := "off"
+	if lock {
+		value = "on"
+	}
+	found := false
+	for _, a := range n.Attrs {
+		sa, ok := a.getSetAttr()
+		if !ok || !n.isSameAttrName(sa.AttrName, "."+attr) {
+			continue
+		}
+		sa.Cmd.setFlag("-l", value)
+		sa.Lock = &lock
+		found = true
+	}
+	if found {
+		return nil
+	}
+	c := NewCmd(fmt.Sprintf("\tsetAttr -l %s %s;", value, quote("."+attr)))
+	parsed, err := ParseSetAttr(c, nil)
+	if err != nil {
+		return err
+	}
+	o.insertCmdAfter(n.lastCmd(), c)
+	n.Attrs = append(n.Attrs, &Attr{Node: n, attrCmd: parsed})
+	return nil
+}
+
+// lastCmd returns the last command of the node: its last attribute,
+// rename or createNode.
+func (n *Node) lastCmd() *Cmd {
+	if len(n.Attrs) != 0 {
+		return n.Attrs[len(n.Attrs)-1].getCmd()
+	}
+	if n.renameCmd != nil {
+		return n.renameCmd.Cmd
+	}
+	return n.createNodeCmd.Cmd
+}
+
+// insertCmdAfter inserts c after prev, or at the end when prev is not
+// found.
+func (o *Object) insertCmdAfter(prev, c *Cmd) {
+	i := o.indexOfCmd(prev)
+	if i == -1 {
+		o.cmds = append(o.cmds, c)
+		return
+	}
+	o.insertCmd(i+1, c)
+}
+
+// unescapeArguments unescapes the arguments of a reference edit, which
+// are written as an escaped string.
+func unescapeArguments(args string) string {
+	if s, err := strconv.Unquote("\"" + args + "\""); err == nil {
+		args = s
+	}
+	args = strings.TrimRight(args, " \t")
+	if args != "" && !strings.HasPrefix(args, " ") {
+		args = " " + args
+	}
+	return args
+}
+
+// ReferenceEditStatus is the result of a reference edit applied by
+// ApplyReferenceEdits.
+type ReferenceEditStatus int
+
+const (
+	// ReferenceEditAny matches every edit.
+	ReferenceEditAny ReferenceEditStatus = iota
+	// ReferenceEditSucceeded matches the edits applied to a loaded reference.
+	ReferenceEditSucceeded
+	// ReferenceEditFailed matches the edits that could not be applied.
+	ReferenceEditFailed
+)
+
+// ReferenceEditFilter selects reference edits. Empty fields match every
+// edit.
+type ReferenceEditFilter struct {
+	Types []ReferenceEditsCmdType
+	// Node is a node name or a DAG path that the edit refers to.
+	Node string
+	// Attr is a long or short attribute name like "translateX" or "tx".
+	// The indices like "[0]" of the names are ignored.
+	Attr string
+	// Status other than ReferenceEditAny needs ApplyReferenceEdits.
+	Status ReferenceEditStatus
+}
+
+// ReferenceEditCmd is a reference edit of a reference node.
+type ReferenceEditCmd struct {
+	// ReferenceNode is the reference node that the edit is applied to.
+	ReferenceNode string
+	Type          ReferenceEditsCmdType
+	// Edit is one of *RECmdParent, *RECmdAddAttr, *RECmdSetAttr,
+	// *RECmdDisconnectAttr, *RECmdDeleteAttr, *RECmdConnectAttr,
+	// *RECmdRelationship, *RECmdLock and *RECmdUnlock.
+	Edit fmt.Stringer
+}
+
+// getCmds returns the edits in the order of the file. The edits that were
+// not parsed, appended to the slices of re, follow them by the type.
+func (re *ReferenceEdit) getCmds() []*ReferenceEditCmd {
+	var cmds []*ReferenceEditCmd
+	add := func(t ReferenceEditsCmdType, edit fmt.Stringer) {
+		cmds = append(cmds, &ReferenceEditCmd{
+			ReferenceNode: re.ReferenceNode,
+			Type:          t,
+			Edit:          edit,
+		})
+	}
+	for _, c := range re.Parents {
+		if c != nil {
+			add(RETypePArent, c)
+		}
+	}
+	for _, c := range re.AddAttrs {
+		if c != nil {
+			add(RETypeAddAttr, c)
+		}
+	}
+	for _, c := range re.SetAttrs {
+		if c != nil {
+			add(RETypeSetAttr, c)
+		}
+	}
+	for _, c := range re.DisconnectAttrs {
+		if c != nil {
+			add(RETypeDisconnectAttr, c)
+		}
+	}
+	for _, c := range re.DeleteAttrs {
+		if c != nil {
+			add(RETypeDeleteAttr, c)
+		}
+	}
+	for _, c := range re.ConnectAttrs {
+		if c != nil {
+			add(RETypeConnectAttr, c)
+		}
+	}
+	for _, c := range re.Relationships {
+		if c != nil {
+			add(RETypeRelationship, c)
+		}
+	}
+	for _, c := range re.Locks {
+		if c != nil {
+			add(RETypeLock, c)
+		}
+	}
+	for _, c := range re.Unlocks {
+		if c != nil {
+			add(RETypeUnlock, c)
+		}
+	}
+	order := map[fmt.Stringer]int{}
+	for i, edit := range re.edits {
+		order[edit] = i
+	}
+	indexOf := func(c *ReferenceEditCmd) int {
+		if i, ok := order[c.Edit]; ok {
+			return i
+		}
+		return len(re.edits)
+	}
+	sort.SliceStable(cmds, func(i, j int) bool {
+		return indexOf(cmds[i]) < indexOf(cmds[j])
+	})
+	return cmds
+}
+
+// removeCmds removes the edits in removed and updates CommandNum.
+func (re *ReferenceEdit) removeCmds(removed map[fmt.Stringer]struct{}) {
+	isKept := func(edit fmt.Stringer) bool {
+		_, ok := removed[edit]
+		return !ok
+	}
+	var parents []*RECmdParent
+	for _, c := range re.Parents {
+		if c != nil && isKept(c) {
+			parents = append(parents, c)
+		}
+	}
+	re.Parents = parents
+	var addAttrs []*RECmdAddAttr
+	for _, c := range re.AddAttrs {
+		if c != nil && isKept(c) {
+			addAttrs = append(addAttrs, c)
+		}
+	}
+	re.AddAttrs = addAttrs
+	var setAttrs []*RECmdSetAttr
+	for _, c := range re.SetAttrs {
+		if c != nil && isKept(c) {
+			setAttrs = append(setAttrs, c)
+		}
+	}
+	re.SetAttrs = setAttrs
+	var disconnectAttrs []*RECmdDisconnectAttr
+	for _, c := range re.DisconnectAttrs {
+		if c != nil && isKept(c) {
+			disconnectAttrs = append(disconnectAttrs, c)
+		}
+	}
+	re.DisconnectAttrs = disconnectAttrs
+	var deleteAttrs []*RECmdDeleteAttr
+	for _, c := range re.DeleteAttrs {
+		if c != nil && isKept(c) {
+			deleteAttrs = append(deleteAttrs, c)
+		}
+	}
+	re.DeleteAttrs = deleteAttrs
+	var connectAttrs []*RECmdConnectAttr
+	for _, c := range re.ConnectAttrs {
+		if c != nil && isKept(c) {
+			connectAttrs = append(connectAttrs, c)
+		}
+	}
+	re.ConnectAttrs = connectAttrs
+	var relationships []*RECmdRelationship
+	for _, c := range re.Relationships {
+		if c != nil && isKept(c) {
+			relationships = append(relationships, c)
+		}
+	}
+	re.Relationships = relationships
+	var locks []*RECmdLock
+	for _, c := range re.Locks {
+		if c != nil && isKept(c) {
+			locks = append(locks, c)
+		}
+	}
+	re.Locks = locks
+	var unlocks []*RECmdUnlock
+	for _, c := range re.Unlocks {
+		if c != nil && isKept(c) {
+			unlocks = append(unlocks, c)
+		}
+	}
+	re.Unlocks = unlocks
+	var edits []fmt.Stringer
+	for _, edit := range re.edits {
+		if isKept(edit) {
+			edits = append(edits, edit)
+		}
+	}
+	re.edits = edits
+	re.CommandNum = len(re.getCmds())
+}
+
+// getTargets returns the nodes and the attributes that the edit refers to.
+func (c *ReferenceEditCmd) getTargets() ([]string, []string) {
+	splitPlug := func(plug string) (string, string) {
+		sep := strings.LastIndex(plug, "|")
+		dotIndex := strings.Index(plug[sep+1:], ".")
+		if dotIndex == -1 {
+			return plug, ""
+		}
+		return plug[:sep+1+dotIndex], plug[sep+2+dotIndex:]
+	}
+	switch e := c.Edit.(type) {
+	case *RECmdParent:
+		return []string{e.NodeA, e.NodeB}, nil
+	case *RECmdAddAttr:
+		return []string{strings.Trim(e.Node, "\"")}, []string{e.LongAttr, e.ShortAttr}
+	case *RECmdSetAttr:
+		return []string{e.Node}, []string{e.Attr}
+	case *RECmdDisconnectAttr:
+		srcNode, srcAttr := splitPlug(e.SourcePlug)
+		dstNode, dstAttr := splitPlug(e.DistPlug)
+		return []string{srcNode, dstNode}, []string{srcAttr, dstAttr}
+	case *RECmdDeleteAttr:
+		return []string{e.Node}, []string{e.Attr}
+	case *RECmdConnectAttr:
+		srcNode, srcAttr := splitPlug(e.SourcePlug)
+		dstNode, dstAttr := splitPlug(e.DistPlug)
+		return []string{srcNode, dstNode}, []string{srcAttr, dstAttr}
+	case *RECmdRelationship:
+		return []string{e.NodeName}, nil
+	case *RECmdLock:
+		return []string{e.Node}, []string{e.Attr}
+	case *RECmdUnlock:
+		return []string{e.Node}, []string{e.Attr}
+	}
+	return nil, nil
+}
+
+// isSameNodePath reports whether the node name or DAG path a refers to
+// the node name or DAG path b.
+func isSameNodePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	lastName := func(path string) string {
+		comps := strings.Split(path, "|")
+		return strings.TrimPrefix(comps[len(comps)-1], ":")
+	}
+	if strings.Contains(a, "|") && strings.Contains(b, "|") {
+		return false
+	}
+	return lastName(a) == lastName(b)
+}
+
+func (o *Object) matchReferenceEdit(c *ReferenceEditCmd, filter *ReferenceEditFilter) bool {
+	if len(filter.Types) != 0 {
+		found := false
+		for _, t := range filter.Types {
+			if t == c.Type {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	nodes, attrs := c.getTargets()
+	if filter.Node != "" {
+		found := false
+		for _, n := range nodes {
+			if n != "" && isSameNodePath(n, filter.Node) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.Attr != "" {
+		target := o.findReferenceObject(c.ReferenceNode)
+		filterAttr := "." + trimAttrIndices(filter.Attr)
+		found := false
+		for i, a := range attrs {
+			if a == "" {
+				continue
+			}
+			a = "." + trimAttrIndices(a)
+			// The attributes of addAttr share the node.
+			var n *Node
+			if j := i; target != nil && len(nodes) != 0 {
+				if len(nodes) <= j {
+					j = len(nodes) - 1
+				}
+				n, _ = o.findEditNode(target, nodes[j])
+			}
+			if n != nil && n.isSameAttrName(a, filterAttr) ||
+				n == nil && isSameAttrNameOfAnyType(a, filterAttr) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	switch filter.Status {
+	case ReferenceEditSucceeded, ReferenceEditFailed:
+		if o.findReferenceObject(c.ReferenceNode) == nil {
+			return false
+		}
+		_, failed := o.failedReferenceEdits[c.Edit]
+		return failed == (filter.Status == ReferenceEditFailed)
+	}
+	return true
+}
+
+// referenceEdits returns the dataReferenceEdits values of the reference
+// node.
+func (n *Node) referenceEdits() ([]*Attr, error) {
+	if n.GetType() != "reference" {
+		return nil, errors.New(fmt.Sprintf("%s is not reference node", n.GetName()))
+	}
+	var attrs []*Attr
+	for _, a := range n.Attrs {
+		sa, ok := a.getSetAttr()
+		if ok && sa.AttrType == SetAttrTypeDataReferenceEdits {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs, nil
+}
+
+// ListReferenceEdits returns the reference edits of the reference node
+// that match the filter. A nil filter matches every edit.
+func (n *Node) ListReferenceEdits(filter *ReferenceEditFilter) ([]*ReferenceEditCmd, error) {
+	if filter == nil {
+		filter = &ReferenceEditFilter{}
+	}
+	if filter.Status != ReferenceEditAny && !n.object.referenceEditsApplied {
+		return nil, errors.New("reference edits were not applied")
+	}
+	attrs, err := n.referenceEdits()
+	if err != nil {
+		return nil, err
+	}
+	var results []*ReferenceEditCmd
+	for _, a := range attrs {
+		edits, err := ToAttrDataReferenceEdits(a.GetAttrValue())
+		if err != nil {
+			return nil, err
+		}
+		for _, ed := range edits {
+			for _, re := range ed.ReferenceEdits {
+				if re == nil {
+					continue
+				}
+				for _, c := range re.getCmds() {
+					if n.object.matchReferenceEdit(c, filter) {
+						results = append(results, c)
+					}
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// RemoveReferenceEdits removes the reference edits of the reference node
+// that match the filter, like the "Remove Edits" of the Reference Editor.
+// The dataReferenceEdits value is rewritten with the new CommandNum,
+// keeping the order of the other edits.
+// It returns the number of the removed edits.
+func (n *Node) RemoveReferenceEdits(filter *ReferenceEditFilter) (int, error) {
+	removed, err := n.ListReferenceEdits(filter)
+	if err != nil {
+		return 0, err
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	removedSet := map[fmt.Stringer]struct{}{}
+	for _, c := range removed {
+		removedSet[c.Edit] = struct{}{}
+	}
+	attrs, err := n.referenceEdits()
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range attrs {
+		edits, err := ToAttrDataReferenceEdits(a.GetAttrValue())
+		if err != nil {
+			return 0, err
+		}
+		for _, ed := range edits {
+			for _, re := range ed.ReferenceEdits {
+				if re != nil {
+					re.removeCmds(removedSet)
+				}
+			}
+		}
+		sa, _ := a.getSetAttr()
+		sa.Cmd.retokenize(referenceEditsRaw(sa))
+	}
+	for edit := range removedSet {
+		delete(n.object.failedReferenceEdits, edit)
+	}
+	return len(removed), nil
+}
+
+// referenceEditsRaw returns the setAttr command of the dataReferenceEdits
+// value.
+func referenceEditsRaw(sa *SetAttrCmd) string {
+	var buf strings.Builder
+	buf.WriteString("\tsetAttr ")
+	buf.WriteString(quote(sa.AttrName))
+	buf.WriteString(" -type \"dataReferenceEdits\" ")
+	for _, a := range sa.Attr {
+		buf.WriteString(a.String())
+	}
+	return strings.TrimRight(buf.String(), "\n") + ";"
+}
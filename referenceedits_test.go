@@ -0,0 +1,370 @@
+package mayaascii
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type mapReferenceResolver map[string]string
+
+func (r mapReferenceResolver) Resolve(p string) (io.ReadCloser, string, error) {
+	s, ok := r[p]
+	if !ok {
+		return nil, "", ErrReferenceNotFound
+	}
+	return ioutil.NopCloser(strings.NewReader(s)), p, nil
+}
+
+func getReferenceEditsTestMa() (string, mapReferenceResolver) {
+	return `//Maya ASCII 2019 scene
+file -rdi 1 -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";
+file -rdi 2 -ns "rig" -rfn "char:rigRN" -typ "mayaAscii" "rig.ma";
+file -r -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";
+createNode transform -n "shotGrp";
+createNode reference -n "charRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"charRN"
+		"charRN" 8
+		0 "|char:root|char:grp|char:geo" "|shotGrp" "-s -r "
+		1 "|char:root" "blend" "bl" " -ci true -k true -at \"double\""
+		2 "|char:root" "blend" " 0.5"
+		2 "|char:root|char:grp|char:geo" "translate" " -type \"double3\" 1 2 3"
+		3 "|char:root|char:grp.scale" "|char:root|char:grp|char:geo.scale" ""
+		5 3 "charRN" "|shotGrp.translate" "|char:root.rotate" ""
+		8 "|char:root" "translateX"
+		2 "|char:missing" "visibility" " 1"
+		"char:rigRN" 1
+		2 "|char:rig:ctrl" "scaleX" " 2";
+`, mapReferenceResolver{
+		"char.ma": `//Maya ASCII 2019 scene
+file -r -ns "rig" -rfn "rigRN" -typ "mayaAscii" "rig.ma";
+createNode transform -n "root";
+	setAttr ".tx" 1;
+createNode transform -n "grp" -p "root";
+createNode transform -n "geo" -p "grp";
+	setAttr ".t" -type "double3" 0 0 0 ;
+createNode reference -n "rigRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"rigRN"
+		"rigRN" 1
+		2 "|rig:ctrl" "visibility" " 0";
+connectAttr "rig:ctrl.t" "root.t";
+connectAttr "grp.scale" "geo.scale";
+connectAttr ":time1.o" "root.tx";`,
+		"rig.ma": `//Maya ASCII 2019 scene
+createNode transform -n "ctrl";
+	setAttr ".v" yes;`,
+	}
+}
+
+func TestObject_ApplyReferenceEdits(t *testing.T) {
+	scene, resolver := getReferenceEditsTestMa()
+	mo, err := Unmarshal(strings.NewReader(scene))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.LoadReferences(resolver, 0); err != nil {
+		t.Fatal(err)
+	}
+	err = mo.ApplyReferenceEdits()
+	var errs ReferenceEditErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, wont ReferenceEditErrors", err)
+	}
+	if len(errs) != 1 || errs[0].ReferenceNode != "charRN" {
+		t.Errorf("got %v, wont a missing node error of charRN", errs)
+	}
+
+	char := mo.Files[2].Object
+	rig := char.Files[0].Object
+	root, err := char.GetNode("char:root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo, err := char.GetNode("char:geo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl, err := rig.GetNode("char:rig:ctrl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []stringTestData{
+		{"char.Files[0].GetReferenceNode()", char.Files[0].GetReferenceNode(), "char:rigRN"},
+		{"geo.Parent.GetName()", geo.Parent.GetName(), "shotGrp"},
+		{"geo Raw", geo.createNodeCmd.Raw,
+			`createNode transform -n "char:geo" -p "shotGrp";`},
+		{"geo .t Raw", geo.GetAttr(".t").getCmd().Raw,
+			"\tsetAttr \".t\" -type \"double3\" 1 2 3;"},
+		{"root blend Raw", root.GetAttr("blend").getCmd().Raw,
+			"\taddAttr -ln \"blend\" -sn \"bl\" -ci true -k true -at \"double\";"},
+		{"root .blend Raw", root.GetAttr(".blend").getCmd().Raw,
+			"\tsetAttr \".blend\" 0.5;"},
+		{"root .tx Raw", root.GetAttr(".tx").getCmd().Raw,
+			"\tsetAttr -l on \".tx\" 1;"},
+		{"ctrl .v Raw", ctrl.GetAttr(".v").getCmd().Raw,
+			"\tsetAttr \".v\" 0;"},
+		{"ctrl .scaleX Raw", ctrl.GetAttr(".scaleX").getCmd().Raw,
+			"\tsetAttr \".scaleX\" 2;"},
+	} {
+		stringTester(d, t)
+	}
+	intTester(intTestData{"len(grp.Children)",
+		len(char.Nodes["char:grp"].Children), 0}, t)
+	boolTester(boolTestData{"root .blend is after addAttr",
+		char.indexOfCmd(root.GetAttr("blend").getCmd()) <
+			char.indexOfCmd(root.GetAttr(".blend").getCmd()), true}, t)
+
+	var plugs []string
+	for _, ca := range char.connections.source {
+		plugs = append(plugs, ca.SrcNode+"."+ca.SrcAttr+">"+ca.DstNode+"."+ca.DstAttr)
+	}
+	stringTester(stringTestData{"char connections", strings.Join(plugs, ","),
+		"char:rig:ctrl.t>char:root.t,:time1.o>char:root.tx," +
+			"shotGrp.translate>char:root.rotate"}, t)
+
+	if err := mo.ApplyReferenceEdits(); err != nil {
+		t.Errorf("got %v, wont nil for the second call", err)
+	}
+	if _, err := char.GetNode("char:root"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestObject_ApplyReferenceEdits_placeHolderList(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+file -r -ns "char" -rfn "charRN" -typ "mayaAscii" "char.ma";
+createNode transform -n "shotGrp";
+createNode reference -n "charRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"charRN"
+		"charRN" 3
+		5 3 "charRN" "|char:root.translate" "charRN.placeHolderList[1]" ""
+		3 "|char:root.rotate" "charRN.placeHolderList[2]" ""
+		5 3 "charRN" "|char:root.scale" "|char:geo.scale" "";
+connectAttr "charRN.phl[1]" "shotGrp.t";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.LoadReferences(mapReferenceResolver{
+		"char.ma": `//Maya ASCII 2019 scene
+createNode transform -n "root";
+createNode transform -n "geo";
+connectAttr "root.rotate" "geo.rotate";`,
+	}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := mo.ApplyReferenceEdits(); err != nil {
+		t.Fatal(err)
+	}
+	var plugs []string
+	for _, ca := range mo.Files[0].Object.connections.source {
+		plugs = append(plugs, ca.SrcNode+"."+ca.SrcAttr+">"+ca.DstNode+"."+ca.DstAttr)
+	}
+	stringTester(stringTestData{"char connections", strings.Join(plugs, ","),
+		"char:root.rotate>char:geo.rotate,char:root.scale>char:geo.scale"}, t)
+}
+
+func TestCmd_setFlag(t *testing.T) {
+	c := NewCmd(`createNode transform -n "a" -p "b";`)
+	c.setFlag("-p", `"c"`)
+	stringTester(stringTestData{"replace", c.Raw, `createNode transform -n "a" -p "c";`}, t)
+	c.removeFlag("-p")
+	stringTester(stringTestData{"remove", c.Raw, `createNode transform -n "a";`}, t)
+	c.setFlag("-p", `"d"`)
+	stringTester(stringTestData{"add", c.Raw, `createNode transform -p "d" -n "a";`}, t)
+	intTester(intTestData{"len(c.Token)", len(c.Token), 6}, t)
+}
+
+func TestNode_ListReferenceEdits(t *testing.T) {
+	scene, resolver := getReferenceEditsTestMa()
+	mo, err := Unmarshal(strings.NewReader(scene))
+	if err != nil {
+		t.Fatal(err)
+	}
+	charRN, err := mo.GetNode("charRN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := charRN.ListReferenceEdits(
+		&ReferenceEditFilter{Status: ReferenceEditFailed}); err == nil {
+		t.Error("got nil, wont not applied error")
+	}
+	shotGrp, _ := mo.GetNode("shotGrp")
+	if _, err := shotGrp.ListReferenceEdits(nil); err == nil {
+		t.Error("got nil, wont not reference node error")
+	}
+	mo.LoadReferences(resolver, 0)
+	mo.ApplyReferenceEdits()
+
+	for _, d := range []struct {
+		title  string
+		filter *ReferenceEditFilter
+		wont   int
+	}{
+		{"nil", nil, 9},
+		{"setAttr", &ReferenceEditFilter{
+			Types: []ReferenceEditsCmdType{RETypeSetAttr}}, 4},
+		{"char:root", &ReferenceEditFilter{Node: "char:root"}, 4},
+		{"|char:root", &ReferenceEditFilter{Node: "|char:root"}, 4},
+		{"|char:geo", &ReferenceEditFilter{Node: "|char:geo"}, 0},
+		{"blend", &ReferenceEditFilter{Attr: "blend"}, 2},
+		{"char:geo scale", &ReferenceEditFilter{
+			Node: "char:geo", Attr: "scale"}, 1},
+		{"tx", &ReferenceEditFilter{Attr: "tx"}, 1},
+		{"bl", &ReferenceEditFilter{Attr: "bl"}, 2},
+		{"char:geo s", &ReferenceEditFilter{Node: "char:geo", Attr: "s"}, 1},
+		{"t[0]", &ReferenceEditFilter{Attr: "t[0]"}, 2},
+		{"failed", &ReferenceEditFilter{Status: ReferenceEditFailed}, 1},
+		{"succeeded", &ReferenceEditFilter{Status: ReferenceEditSucceeded}, 8},
+	} {
+		edits, err := charRN.ListReferenceEdits(d.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intTester(intTestData{d.title, len(edits), d.wont}, t)
+	}
+}
+
+func TestNode_RemoveReferenceEdits(t *testing.T) {
+	scene, resolver := getReferenceEditsTestMa()
+	mo, err := Unmarshal(strings.NewReader(scene))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mo.LoadReferences(resolver, 0)
+	mo.ApplyReferenceEdits()
+	charRN, _ := mo.GetNode("charRN")
+
+	count, err := charRN.RemoveReferenceEdits(&ReferenceEditFilter{Status: ReferenceEditFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"failed count", count, 1}, t)
+	count, err = charRN.RemoveReferenceEdits(&ReferenceEditFilter{
+		Types: []ReferenceEditsCmdType{RETypeSetAttr, RETypeLock, RETypeAddAttr,
+			RETypeConnectAttr, RETypeDisconnectAttr},
+		Node: "char:root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"char:root count", count, 4}, t)
+
+	stringTester(stringTestData{"charRN .ed Raw", charRN.GetAttr(".ed").getCmd().Raw,
+		"\tsetAttr \".ed\" -type \"dataReferenceEdits\" \n" +
+			"\t\t\"charRN\"\n" +
+			"\t\t\"charRN\" 3\n" +
+			"\t\t0 \"|char:root|char:grp|char:geo\" \"|shotGrp\" \"-s -r \"\n" +
+			"\t\t2 \"|char:root|char:grp|char:geo\" \"translate\" \" -type \\\"double3\\\" 1 2 3\"\n" +
+			"\t\t3 \"|char:root|char:grp.scale\" \"|char:root|char:grp|char:geo.scale\" \"\"\n" +
+			"\t\t\"char:rigRN\" 1\n" +
+			"\t\t2 \"|char:rig:ctrl\" \"scaleX\" \" 2\";"}, t)
+
+	saved, err := Unmarshal(strings.NewReader(
+		"createNode reference -n \"charRN\";\n" + charRN.GetAttr(".ed").getCmd().Raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedRN, _ := saved.GetNode("charRN")
+	edits, err := savedRN.ListReferenceEdits(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len(edits)", len(edits), 4}, t)
+}
+
+func TestNode_RemoveReferenceEdits_interleaved(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode reference -n "charRN";
+	setAttr ".ed" -type "dataReferenceEdits"
+		"charRN"
+		"charRN" 5
+		2 "|char:root" "visibility" " 0"
+		0 "|char:root" "|shotGrp" "-s -r "
+		8 "|char:root" "translateX"
+		2 "|char:geo" "visibility" " 1"
+		2 "|char:root" "scaleX" " 2";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	charRN, _ := o.GetNode("charRN")
+	edits, err := charRN.ListReferenceEdits(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, c := range edits {
+		types = append(types, string(c.Type))
+	}
+	stringTester(stringTestData{"ListReferenceEdits types",
+		strings.Join(types, " "), "2 0 8 2 2"}, t)
+
+	for _, d := range []struct {
+		attr string
+		wont int
+	}{
+		{"v", 2}, {"visibility", 2}, {"sx", 1}, {"tx", 1}, {"tx[0]", 1}, {"ty", 0},
+	} {
+		edits, err := charRN.ListReferenceEdits(&ReferenceEditFilter{Attr: d.attr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		intTester(intTestData{"Attr " + d.attr, len(edits), d.wont}, t)
+	}
+
+	count, err := charRN.RemoveReferenceEdits(&ReferenceEditFilter{Node: "char:geo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"count", count, 1}, t)
+	stringTester(stringTestData{"charRN .ed Raw", charRN.GetAttr(".ed").getCmd().Raw,
+		"\tsetAttr \".ed\" -type \"dataReferenceEdits\" \n" +
+			"\t\t\"charRN\"\n" +
+			"\t\t\"charRN\" 4\n" +
+			"\t\t2 \"|char:root\" \"visibility\" \" 0\"\n" +
+			"\t\t0 \"|char:root\" \"|shotGrp\" \"-s -r \"\n" +
+			"\t\t8 \"|char:root\" \"translateX\"\n" +
+			"\t\t2 \"|char:root\" \"scaleX\" \" 2\";"}, t)
+}
+
+func TestNode_isSameAttrName(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode joint -n "j";
+createNode time -n "time1";
+createNode mesh -n "m";
+createNode myNode -n "p";
+	addAttr -ln "blend" -sn "bl" -at "double";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []struct {
+		node, a, b string
+		wont       bool
+	}{
+		{"j", ".t", ".translate", true},
+		{"j", ".jo", ".jointOrient", true},
+		{"j", ".v", ".visibility", true},
+		{"j", ".msg", ".message", true},
+		{"j", ".pt[0]", ".pnts[0]", false},
+		{"time1", ".o", ".outTime", true},
+		{"time1", ".o", ".output", false},
+		{"m", ".i", ".inMesh", true},
+		{"m", ".i", ".input", false},
+		{"m", ".pt[3].pz", ".pnts[3].pz", true},
+		{"p", ".t", ".translate", false},
+		{"p", ".bl", ".blend", true},
+		{"p", ".msg", ".message", true},
+	} {
+		n, err := o.GetNode(d.node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		boolTester(boolTestData{d.node + d.a + " " + d.b,
+			n.isSameAttrName(d.a, d.b), d.wont}, t)
+	}
+}
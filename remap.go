@@ -0,0 +1,194 @@
+package mayaascii
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilePathAttrs is the attributes that hold a file path, by node type.
+// The attributes added with "addAttr -uaf" also hold a file path.
+var FilePathAttrs = map[string][]string{
+	"file":        {".ftn", ".fileTextureName"},
+	"AlembicNode": {".fn", ".abc_File"},
+	"gpuCache":    {".cfn", ".cacheFileName"},
+	"audio":       {".f", ".filename"},
+	"imagePlane":  {".imn", ".imageName"},
+	"xgmPalette":  {".xfn", ".xgFileName"},
+}
+
+// PathRule rewrites a file path for RemapPaths.
+type PathRule interface {
+	// Remap returns the new path, or false when the rule does not match.
+	Remap(path string) (string, bool)
+}
+
+// PrefixPathRule replaces the directory From at the head of the path with
+// To. From is compared case-insensitively and "\" is treated as "/".
+type PrefixPathRule struct {
+	From string
+	To   string
+}
+
+func (r PrefixPathRule) Remap(path string) (string, bool) {
+	return replacePathPrefix(path, r.From, r.To)
+}
+
+// RegexpPathRule replaces the matches of Regexp with Replacement like
+// regexp.Regexp.ReplaceAllString.
+type RegexpPathRule struct {
+	Regexp      *regexp.Regexp
+	Replacement string
+}
+
+func (r RegexpPathRule) Remap(path string) (string, bool) {
+	if !r.Regexp.MatchString(path) {
+		return path, false
+	}
+	return r.Regexp.ReplaceAllString(path, r.Replacement), true
+}
+
+// EnvPathRule tokenizes the path with an environment variable. The
+// directory Value at the head of the path is replaced with "$Name".
+type EnvPathRule struct {
+	Name  string
+	Value string
+}
+
+func (r EnvPathRule) Remap(path string) (string, bool) {
+	return replacePathPrefix(path, r.Value, "$"+r.Name)
+}
+
+// replacePathPrefix replaces the directory from at the head of p with to.
+func replacePathPrefix(p, from, to string) (string, bool) {
+	p = strings.Replace(p, "\\", "/", -1)
+	from = strings.TrimRight(strings.Replace(from, "\\", "/", -1), "/")
+	if from == "" || len(p) < len(from) || !strings.EqualFold(p[:len(from)], from) {
+		return p, false
+	}
+	rest := p[len(from):]
+	if rest != "" && rest[0] != '/' {
+		return p, false
+	}
+	return strings.TrimRight(strings.Replace(to, "\\", "/", -1), "/") + rest, true
+}
+
+// remapPath applies every rule to p in order.
+func remapPath(p string, rules []PathRule) (string, bool) {
+	changed := false
+	for _, r := range rules {
+		if remapped, ok := r.Remap(p); ok {
+			p = remapped
+			changed = true
+		}
+	}
+	return p, changed
+}
+
+// filePathAttr is a setAttr of a file path.
+type filePathAttr struct {
+	node    *Node
+	setAttr *SetAttrCmd
+	value   *AttrString
+}
+
+// getPath returns the unescaped file path.
+func (a *filePathAttr) getPath() string {
+	return unescapeMayaString(a.value.String())
+}
+
+// isFilePathAttr reports whether the attribute of n holds a file path.
+func (n *Node) isFilePathAttr(name string) bool {
+	for _, attr := range FilePathAttrs[n.GetType()] {
+		if attr == name {
+			return true
+		}
+	}
+	for _, a := range n.Attrs {
+		aa, ok := a.attrCmd.(*AddAttrCmd)
+		if !ok || !aa.UsedAsFilename {
+			continue
+		}
+		if (aa.LongName != nil && "."+*aa.LongName == name) ||
+			(aa.ShortName != nil && "."+*aa.ShortName == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filePathAttrs returns the string setAttr values that hold a file path.
+func (o *Object) filePathAttrs() []*filePathAttr {
+	var results []*filePathAttr
+	for _, n := range o.sortedNodes() {
+		if n.isDeleted {
+			continue
+		}
+		for _, a := range n.Attrs {
+			sa, ok := a.getSetAttr()
+			if !ok || a.isDeleted || sa.AttrType != SetAttrTypeString || len(sa.Attr) == 0 {
+				continue
+			}
+			if !n.isFilePathAttr(sa.AttrName) {
+				continue
+			}
+			value, ok := sa.Attr[0].(*AttrString)
+			if !ok {
+				continue
+			}
+			results = append(results, &filePathAttr{node: n, setAttr: sa, value: value})
+		}
+	}
+	return results
+}
+
+// RemapPaths rewrites the paths of the references ("file -r" and
+// "file -rdi") and of the file path attributes (see FilePathAttrs) with
+// rules. Every rule is applied to the path in order, so a prefix rule can
+// be followed by an EnvPathRule. It returns the number of rewritten paths.
+func (o *Object) RemapPaths(rules []PathRule) int {
+	count := 0
+	for _, f := range o.Files {
+		p, ok := remapPath(f.fileCmd.Path, rules)
+		if !ok || p == f.fileCmd.Path {
+			continue
+		}
+		f.fileCmd.Cmd.replaceToken(quote(f.fileCmd.Path), quote(p))
+		f.fileCmd.Path = p
+		count++
+	}
+	for _, a := range o.filePathAttrs() {
+		p, ok := remapPath(a.getPath(), rules)
+		if !ok || p == a.getPath() {
+			continue
+		}
+		escaped := escapeMayaString(p)
+		a.setAttr.Cmd.replaceToken(quote(a.value.String()), quote(escaped))
+		*a.value = AttrString(escaped)
+		count++
+	}
+	return count
+}
+
+// unescapeMayaString unescapes a string value written in a .ma file.
+func unescapeMayaString(s string) string {
+	if unquoted, err := strconv.Unquote("\"" + s + "\""); err == nil {
+		return unquoted
+	}
+	return s
+}
+
+// mayaStringEscaper escapes the backslashes, the quotes and the control
+// characters of a string value.
+var mayaStringEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+)
+
+// escapeMayaString escapes s to write it as a string value.
+func escapeMayaString(s string) string {
+	return mayaStringEscaper.Replace(s)
+}
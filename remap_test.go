@@ -0,0 +1,77 @@
+package mayaascii
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func getRemapTestMa() string {
+	return `//Maya ASCII 2019 scene
+file -rdi 1 -ns "char" -rfn "charRN" -typ "mayaAscii" "C:/projects/show/char.ma";
+file -r -ns "char" -dr 1 -rfn "charRN" -typ "mayaAscii" "C:/projects/show/char.ma";
+createNode file -n "file1";
+	setAttr ".ftn" -type "string" "C:\\projects\\show\\tex\\wood.png";
+createNode AlembicNode -n "abc1";
+	setAttr ".fn" -type "string" "C:/projects/show/cache/a.abc";
+createNode gpuCache -n "gpu1";
+	setAttr ".cacheFileName" -type "string" "C:/projects2/gpu.abc";
+createNode transform -n "custom";
+	addAttr -ci true -uaf -sn "cf" -ln "cacheFile" -dt "string";
+	addAttr -ci true -sn "nt" -ln "note" -dt "string";
+	setAttr ".cf" -type "string" "C:/projects/show/custom.abc";
+	setAttr ".nt" -type "string" "C:/projects/show/note.txt";`
+}
+
+func TestObject_RemapPaths(t *testing.T) {
+	mo, err := Unmarshal(strings.NewReader(getRemapTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := mo.RemapPaths([]PathRule{
+		PrefixPathRule{From: "c:/Projects", To: "/mnt/projects"},
+		RegexpPathRule{Regexp: regexp.MustCompile(`\.abc$`), Replacement: ".v2.abc"},
+		EnvPathRule{Name: "SHOW", Value: "/mnt/projects/show"},
+	})
+	intTester(intTestData{"count", count, 6}, t)
+
+	file1, _ := mo.GetNode("file1")
+	abc1, _ := mo.GetNode("abc1")
+	gpu1, _ := mo.GetNode("gpu1")
+	custom, _ := mo.GetNode("custom")
+	for _, d := range []stringTestData{
+		{"mo.Files[0].GetPath()", mo.Files[0].GetPath(), "$SHOW/char.ma"},
+		{"mo.Files[1] Raw", mo.Files[1].fileCmd.Raw,
+			`file -r -ns "char" -dr 1 -rfn "charRN" -typ "mayaAscii" "$SHOW/char.ma";`},
+		{"file1 .ftn Raw", file1.GetAttr(".ftn").getCmd().Raw,
+			"\tsetAttr \".ftn\" -type \"string\" \"$SHOW/tex/wood.png\";"},
+		{"abc1 .fn", abc1.GetAttr(".fn").GetAttrValue()[0].String(), "$SHOW/cache/a.v2.abc"},
+		{"gpu1 .cacheFileName", gpu1.GetAttr(".cacheFileName").GetAttrValue()[0].String(),
+			"C:/projects2/gpu.v2.abc"},
+		{"custom .cf", custom.GetAttr(".cf").GetAttrValue()[0].String(), "$SHOW/custom.v2.abc"},
+		{"custom .nt", custom.GetAttr(".nt").GetAttrValue()[0].String(),
+			"C:/projects/show/note.txt"},
+	} {
+		stringTester(d, t)
+	}
+}
+
+func TestPrefixPathRule_Remap(t *testing.T) {
+	r := PrefixPathRule{From: "C:\\projects\\", To: "/mnt/projects"}
+	for _, d := range []struct {
+		path string
+		wont string
+		ok   bool
+	}{
+		{"C:/projects/a.ma", "/mnt/projects/a.ma", true},
+		{"c:\\PROJECTS\\a.ma", "/mnt/projects/a.ma", true},
+		{"C:/projects", "/mnt/projects", true},
+		{"C:/projects2/a.ma", "", false},
+	} {
+		p, ok := r.Remap(d.path)
+		boolTester(boolTestData{d.path, ok, d.ok}, t)
+		if ok {
+			stringTester(stringTestData{d.path, p, d.wont}, t)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// nodeNameRegexp matches "name" and "ns:name" with any namespace depth.
+var nodeNameRegexp = regexp.MustCompile(
+	`^:?([A-Za-z_][A-Za-z0-9_]*:)*[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidNodeName reports whether name can be used as a Maya node name.
+func IsValidNodeName(name string) bool {
+	return nodeNameRegexp.MatchString(name)
+}
+
+// uniqueNodeName returns name, or name with an incremented trailing number
+// when the name is already used. Same as Maya's "pCube1" -> "pCube2".
+func (o *Object) uniqueNodeName(name string) string {
+	return uniqueName(name, func(candidate string) bool {
+		_, ok := o.Nodes[candidate]
+		return ok
+	})
+}
+
+func uniqueName(name string, used func(string) bool) string {
+	if !used(name) {
+		return name
+	}
+	base := strings.TrimRight(name, "0123456789")
+	num := 1
+	if len(base) != len(name) {
+		n, err := strconv.Atoi(name[len(base):])
+		if err == nil {
+			num = n + 1
+		}
+	}
+	for {
+		candidate := base + strconv.Itoa(num)
+		if !used(candidate) {
+			return candidate
+		}
+		num++
+	}
+}
+
+// Rename renames the node and every reference to it in the scene:
+// connectAttr, the -p flag of child createNode, select,
+// the -rfn flag of file and the reference edits that address the node.
+// A name conflict is resolved like Maya does, so the returned name can
+// differ from newName.
+func (n *Node) Rename(newName string) (string, error) {
+	if n.isDeleted {
+		return "", errors.New(fmt.Sprintf("%s was already deleted", n.GetName()))
+	}
+	newName = strings.TrimPrefix(newName, ":")
+	if !IsValidNodeName(newName) {
+		return "", errors.New(fmt.Sprintf("\"%s\" is invalid node name", newName))
+	}
+	oldName := n.GetName()
+	if newName == oldName {
+		return newName, nil
+	}
+	newName = n.object.uniqueNodeName(newName)
+	n.object.renameNodeRefs(n, newName)
+
+	n.object.setNodeName(n, newName)
+	return newName, nil
+}
+
+// setNodeName rewrites the -n flag of createNode and the key of Nodes.
+func (o *Object) setNodeName(n *Node, newName string) {
+	oldName := n.GetName()
+	n.createNodeCmd.Cmd.replaceToken(quote(oldName), quote(newName))
+	n.createNodeCmd.NodeName = newName
+	delete(o.Nodes, oldName)
+	o.Nodes[newName] = n
+}
+
+// renameNodeRefs rewrites every name, DAG path and plug that refers to n.
+func (o *Object) renameNodeRefs(n *Node, newName string) {
+	o.rewriteNodeRefs(func(path string) (string, bool) {
+		return renameNodePath(path, n, newName)
+	})
+}
+
+// rewriteNodeRefs rewrites the node names and DAG paths of connectAttr,
+// the -p flag of createNode, select, the -rfn flag of file and
+// reference edits with mapPath. The createNode -n flag is not rewritten.
+func (o *Object) rewriteNodeRefs(mapPath func(path string) (string, bool)) {
+	mapPlug := func(plug string) (string, bool) {
+		dotIndex := strings.Index(plug, ".")
+		if dotIndex == -1 {
+			return mapPath(plug)
+		}
+		node, ok := mapPath(plug[:dotIndex])
+		if !ok {
+			return plug, false
+		}
+		return node + plug[dotIndex:], true
+	}
+
+	for _, ca := range o.connections.source {
+		if src, ok := mapPath(ca.SrcNode); ok {
+			ca.Cmd.replaceToken(
+				quote(ca.SrcNode+"."+ca.SrcAttr), quote(src+"."+ca.SrcAttr))
+			ca.SrcNode = src
+		}
+		if dst, ok := mapPath(ca.DstNode); ok {
+			ca.Cmd.replaceToken(
+				quote(ca.DstNode+"."+ca.DstAttr), quote(dst+"."+ca.DstAttr))
+			ca.DstNode = dst
+		}
+	}
+
+	for _, node := range o.Nodes {
+		if node.createNodeCmd.Parent == nil {
+			continue
+		}
+		parent := *node.createNodeCmd.Parent
+		if p, ok := mapPath(parent); ok {
+			node.createNodeCmd.Cmd.replaceToken(quote(parent), quote(p))
+			node.createNodeCmd.Parent = &p
+		}
+	}
+
+	for _, s := range o.Selects {
+		for i, name := range s.selectCmd.Names {
+			if mapped, ok := mapPath(name); ok {
+				s.selectCmd.Cmd.replaceToken(name, mapped)
+				s.selectCmd.Names[i] = mapped
+			}
+		}
+	}
+
+	for _, f := range o.Files {
+		if rfn, ok := mapPath(f.fileCmd.ReferenceNode); ok {
+			f.fileCmd.Cmd.replaceToken(quote(f.fileCmd.ReferenceNode), quote(rfn))
+			f.fileCmd.ReferenceNode = rfn
+		}
+	}
+
+	for _, a := range o.referenceEditsAttrs() {
+		for _, edits := range a.edits {
+			rewriteReferenceEdits(a.cmd, edits, mapPath, mapPlug)
+		}
+	}
+}
+
+type referenceEditsAttr struct {
+	cmd   *Cmd
+	edits []*AttrDataReferenceEdits
+}
+
+// referenceEditsAttrs returns every dataReferenceEdits value of the scene.
+func (o *Object) referenceEditsAttrs() []referenceEditsAttr {
+	var attrs []*Attr
+	for _, n := range o.sortedNodes() {
+		attrs = append(attrs, n.Attrs...)
+	}
+	for _, s := range o.Selects {
+		attrs = append(attrs, s.Attrs...)
+	}
+	var results []referenceEditsAttr
+	for _, a := range attrs {
+		sa, ok := a.getSetAttr()
+		if !ok || sa.AttrType != SetAttrTypeDataReferenceEdits {
+			continue
+		}
+		edits, err := ToAttrDataReferenceEdits(sa.Attr)
+		if err != nil {
+			continue
+		}
+		results = append(results, referenceEditsAttr{cmd: sa.Cmd, edits: edits})
+	}
+	return results
+}
+
+func rewriteReferenceEdits(
+	c *Cmd,
+	ed *AttrDataReferenceEdits,
+	mapPath func(string) (string, bool),
+	mapPlug func(string) (string, bool)) {
+	rename := func(name *string) {
+		if mapped, ok := mapPath(*name); ok {
+			c.replaceToken(quote(*name), quote(mapped))
+			*name = mapped
+		}
+	}
+	renamePlug := func(plug *string) {
+		if mapped, ok := mapPlug(*plug); ok {
+			c.replaceToken(quote(*plug), quote(mapped))
+			*plug = mapped
+		}
+	}
+	rename(&ed.TopReferenceNode)
+	for _, re := range ed.ReferenceEdits {
+		if re == nil {
+			continue
+		}
+		rename(&re.ReferenceNode)
+		for _, prt := range re.Parents {
+			rename(&prt.NodeA)
+			rename(&prt.NodeB)
+		}
+		for _, aa := range re.AddAttrs {
+			// RECmdAddAttr keeps the node token as it is.
+			node := strings.Trim(aa.Node, "\"")
+			if mapped, ok := mapPath(node); ok {
+				c.replaceToken(aa.Node, strings.Replace(aa.Node, node, mapped, 1))
+				aa.Node = strings.Replace(aa.Node, node, mapped, 1)
+			}
+		}
+		for _, sa := range re.SetAttrs {
+			rename(&sa.Node)
+		}
+		for _, da := range re.DisconnectAttrs {
+			renamePlug(&da.SourcePlug)
+			renamePlug(&da.DistPlug)
+		}
+		for _, da := range re.DeleteAttrs {
+			rename(&da.Node)
+		}
+		for _, ca := range re.ConnectAttrs {
+			rename(&ca.ReferenceNode)
+			renamePlug(&ca.SourcePlug)
+			renamePlug(&ca.DistPlug)
+		}
+		for _, rs := range re.Relationships {
+			rename(&rs.NodeName)
+		}
+		for _, lk := range re.Locks {
+			rename(&lk.Node)
+		}
+		for _, ulk := range re.Unlocks {
+			rename(&ulk.Node)
+		}
+	}
+}
+
+// renameNodePath renames the component of a node name or a DAG path
+// ("|group1|pCube1", ":time1") that refers to n.
+func renameNodePath(path string, n *Node, newName string) (string, bool) {
+	oldName := n.GetName()
+	comps := strings.Split(path, "|")
+	for k := len(comps) - 1; k >= 0; k-- {
+		if strings.TrimPrefix(comps[k], ":") != oldName {
+			continue
+		}
+		if !n.matchAncestors(comps[:k]) {
+			continue
+		}
+		if strings.HasPrefix(comps[k], ":") {
+			comps[k] = ":" + newName
+		} else {
+			comps[k] = newName
+		}
+		return strings.Join(comps, "|"), true
+	}
+	return path, false
+}
+
+// isNodePath reports whether the node name or the DAG path
+// ("|group1|pCube1", ":time1") refers to n.
+func (n *Node) isNodePath(path string) bool {
+	comps := strings.Split(path, "|")
+	if strings.TrimPrefix(comps[len(comps)-1], ":") != n.GetName() {
+		return false
+	}
+	return n.matchAncestors(comps[:len(comps)-1])
+}
+
+// findNodeByPath returns the node of the node name or the DAG path, or
+// nil. A deleted node is not found. The parents of the path are not
+// compared when ignoreParents is true, for the paths of reference edits
+// that are the paths before the parent edits.
+func (o *Object) findNodeByPath(path string, ignoreParents bool) *Node {
+	comps := strings.Split(path, "|")
+	n, ok := o.Nodes[strings.TrimPrefix(comps[len(comps)-1], ":")]
+	if !ok || n.isDeleted || (!ignoreParents && !n.isNodePath(path)) {
+		return nil
+	}
+	return n
+}
+
+// matchAncestors reports whether the DAG path components ancestors can be
+// the parents of n. An empty first component means an absolute path.
+// Ancestors that the parser could not resolve are not compared.
+func (n *Node) matchAncestors(ancestors []string) bool {
+	cur := n
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if cur.Parent == nil {
+			// An unresolved -p flag can't be compared.
+			return cur.createNodeCmd.Parent != nil ||
+				(i == 0 && ancestors[0] == "")
+		}
+		if ancestors[i] == "" {
+			return false
+		}
+		if strings.TrimPrefix(ancestors[i], ":") != cur.Parent.GetName() {
+			return false
+		}
+		cur = cur.Parent
+	}
+	return true
+}
+
+func quote(s string) string {
+	return "\"" + s + "\""
+}
@@ -0,0 +1,179 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+func (r Require) IsUsed() bool {
+	return len(r.Nodes) != 0 || len(r.Data) != 0
+}
+
+// RequiresReport is the result of Object.AnalyzeRequires.
+type RequiresReport struct {
+	// Required is the minimal set of requires the scene needs.
+	Required []*Require
+	// Unused is the requires that no node of the scene uses.
+	Unused []*Require
+	// UnknownNodeTypes is the node types that are neither in the set of
+	// the built-in types nor declared by any requires command. It is a
+	// heuristic: a built-in type missing from the set is also reported.
+	UnknownNodeTypes []string
+}
+
+// AnalyzeRequires checks the requires commands against the -nodeType and
+// -dataType usage of the scene. "maya" itself is always required.
+// A plugin without any -nodeType and -dataType declarations is reported
+// as unused, because Maya declares the types of every plugin node it saves.
+// The built-in node types are MayaNodeTypes.
+func (o *Object) AnalyzeRequires() *RequiresReport {
+	return o.AnalyzeRequiresWithNodeTypes(MayaNodeTypes)
+}
+
+// AnalyzeRequiresWithNodeTypes is AnalyzeRequires with the set of the
+// built-in node types of the caller, like the "allNodeTypes" of the Maya
+// that saved the scene.
+func (o *Object) AnalyzeRequiresWithNodeTypes(builtinNodeTypes map[string]struct{}) *RequiresReport {
+	report := &RequiresReport{
+		Required:         []*Require{},
+		Unused:           []*Require{},
+		UnknownNodeTypes: []string{},
+	}
+	owned := map[string]struct{}{}
+	for _, r := range o.Requires {
+		if r.GetPluginName() == "maya" || r.IsUsed() {
+			report.Required = append(report.Required, r)
+		} else {
+			report.Unused = append(report.Unused, r)
+		}
+		for _, nt := range r.GetNodeTypes() {
+			owned[nt] = struct{}{}
+		}
+	}
+	unknown := map[string]struct{}{}
+	for _, n := range o.Nodes {
+		nt := n.GetType()
+		if _, ok := owned[nt]; ok {
+			continue
+		}
+		if _, ok := builtinNodeTypes[nt]; ok {
+			continue
+		}
+		unknown[nt] = struct{}{}
+	}
+	for nt := range unknown {
+		report.UnknownNodeTypes = append(report.UnknownNodeTypes, nt)
+	}
+	sort.Strings(report.UnknownNodeTypes)
+	return report
+}
+
+func (o *Object) GetRequire(pluginName string) (*Require, error) {
+	for _, r := range o.Requires {
+		if r.GetPluginName() == pluginName {
+			return r, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("%s require was not found", pluginName))
+}
+
+// AddRequire adds a requires command after the last requires of the scene
+// and binds the existing nodes that use its node types and data types.
+func (o *Object) AddRequire(pluginName, version string, nodeTypes, dataTypes []string) (*Require, error) {
+	if _, err := o.GetRequire(pluginName); err == nil {
+		return nil, errors.New(fmt.Sprintf("%s require already exists", pluginName))
+	}
+	rq := &RequiresCmd{
+		PluginName: pluginName,
+		Version:    version,
+		NodeTypes:  nodeTypes,
+		DataTypes:  dataTypes,
+	}
+	rq.Cmd = NewCmd(rq.String())
+	r := &Require{
+		Nodes:      []*Node{},
+		Data:       []*Node{},
+		requireCmd: rq,
+	}
+
+	i := 0
+	for j, c := range o.cmds {
+		if c.Type == TypeRequires || (c.Type == TypeLineComment && i == j) {
+			i = j + 1
+		}
+	}
+	o.insertCmd(i, rq.Cmd)
+	o.Requires = append(o.Requires, r)
+
+	for _, n := range o.sortedNodes() {
+		o.bindRequireNode(n)
+		for _, a := range n.Attrs {
+			if c := a.getCmd(); c != nil {
+				o.bindRequireData(n, c)
+			}
+		}
+	}
+	return r, nil
+}
+
+// RemoveRequire removes the requires command of pluginName.
+// The nodes of the plugin are kept in the scene.
+func (o *Object) RemoveRequire(pluginName string) error {
+	for i, r := range o.Requires {
+		if r.GetPluginName() != pluginName {
+			continue
+		}
+		o.Requires = append(o.Requires[:i], o.Requires[i+1:]...)
+		if r.requireCmd.Cmd != nil {
+			o.removeCmd(r.requireCmd.Cmd)
+		}
+		return nil
+	}
+	return errors.New(fmt.Sprintf("%s require was not found", pluginName))
+}
+
+func (o *Object) bindRequireNode(n *Node) {
+	for _, r := range o.Requires {
+		for _, nt := range r.GetNodeTypes() {
+			if n.GetType() != nt {
+				continue
+			}
+			if !containsNode(r.Nodes, n) {
+				r.Nodes = append(r.Nodes, n)
+			}
+			return
+		}
+	}
+}
+
+func (o *Object) bindRequireData(n *Node, c *Cmd) {
+	o.bindRequireDataType(n, getDataTypeFromAttrCmd(&c.Token))
+}
+
+// bindRequireDataType binds n to the requires of the data type dt.
+func (o *Object) bindRequireDataType(n *Node, dt string) {
+	if dt == "" {
+		return
+	}
+	for _, r := range o.Requires {
+		for _, rdt := range r.GetDataTypes() {
+			if dt != rdt {
+				continue
+			}
+			if !containsNode(r.Data, n) {
+				r.Data = append(r.Data, n)
+			}
+			return
+		}
+	}
+}
+
+func containsNode(nodes []*Node, n *Node) bool {
+	for _, node := range nodes {
+		if node == n {
+			return true
+		}
+	}
+	return false
+}
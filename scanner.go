@@ -0,0 +1,165 @@
+package mayaascii
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Command is a command returned by Scanner.Next. It is one of
+// *LineCommentCmd, *BlockCommentCmd, *FileCmd, *FileInfoCmd,
+// *WorkspaceCmd, *RequiresCmd, *CreateNodeCmd, *RenameCmd, *AddAttrCmd,
+// *SetAttrCmd, *ConnectAttrCmd and *SelectCmd, or *Cmd for the other
+// commands and for the commands that could not be parsed.
+type Command interface {
+	GetCmd() *Cmd
+}
+
+// GetCmd returns the tokenized command.
+func (c *Cmd) GetCmd() *Cmd {
+	return c
+}
+
+// Scanner reads the commands of a Maya ASCII file one by one without
+// building an Object, so the memory use does not depend on the size of
+// the file.
+//
+//	s := mayaascii.NewScanner(reader)
+//	for {
+//		c, err := s.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if sa, ok := c.(*mayaascii.SetAttrCmd); ok {
+//			...
+//		}
+//	}
+type Scanner struct {
+	// SkipAttrValue reports whether the value of the setAttr is not
+	// parsed. The skipped SetAttrCmd has only AttrName and the tokens.
+	// nodeType is "" for the setAttr of select.
+	SkipAttrValue func(nodeType, attrName string) bool
+
+	lr                 *lineReader
+	cmdBuilder         *CmdBuilder
+	lineCommentBuilder *CmdBuilder
+	nodeType           string
+	beforeSetAttr      *SetAttrCmd
+	skippedAttr        string
+}
+
+func NewScanner(reader io.Reader) *Scanner {
+	return &Scanner{
+		lr:                 &lineReader{br: bufio.NewReader(reader)},
+		cmdBuilder:         &CmdBuilder{},
+		lineCommentBuilder: &CmdBuilder{},
+	}
+}
+
+// NextCmd returns the next tokenized command, or io.EOF.
+func (s *Scanner) NextCmd() (*Cmd, error) {
+	for {
+		line, err := s.lr.readLine()
+		if err == io.EOF {
+			if s.cmdBuilder.IsClear() {
+				return nil, io.EOF
+			}
+			// The last command has no ";".
+			c := s.cmdBuilder.Parse()
+			s.cmdBuilder.Clear()
+			return c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !s.cmdBuilder.isInString() && TypeLineComment.HasPrefix(line) {
+			s.lineCommentBuilder.Append(line)
+			c := s.lineCommentBuilder.Parse()
+			s.lineCommentBuilder.Clear()
+			s.cmdBuilder.lineNo++
+			return c, nil
+		}
+		s.cmdBuilder.Append(line)
+		s.lineCommentBuilder.lineNo++
+		if s.cmdBuilder.IsCmdEOF() {
+			c := s.cmdBuilder.Parse()
+			s.cmdBuilder.Clear()
+			return c, nil
+		}
+	}
+}
+
+// Next returns the next typed command, or io.EOF at the end of the file.
+// When the command can't be parsed, Next returns the *Cmd with the error
+// and the next call continues with the next command.
+func (s *Scanner) Next() (Command, error) {
+	c, err := s.NextCmd()
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := s.parse(c)
+	if err != nil {
+		return c, errors.New(fmt.Sprintf("line %d: %s", c.LineNo, err.Error()))
+	}
+	return cmd, nil
+}
+
+func (s *Scanner) parse(c *Cmd) (Command, error) {
+	switch c.Type {
+	case TypeLineComment:
+		return ParseLineComment(c), nil
+	case TypeBlockComment:
+		return ParseBlockComment(c), nil
+	case TypeFile:
+		return ParseFile(c)
+	case TypeFileInfo:
+		return ParseFileInfo(c)
+	case TypeWorkspace:
+		return ParseWorkspace(c)
+	case TypeRequires:
+		return ParseRequires(c)
+	case TypeCreateNode:
+		cn, err := ParseCreateNode(c)
+		if err != nil {
+			return nil, err
+		}
+		s.nodeType = cn.NodeType
+		s.beforeSetAttr = nil
+		s.skippedAttr = ""
+		return cn, nil
+	case TypeSelect:
+		s.nodeType = ""
+		s.beforeSetAttr = nil
+		s.skippedAttr = ""
+		return ParseSelect(c), nil
+	case TypeRename:
+		return ParseRename(c), nil
+	case TypeAddAttr:
+		return ParseAddAttr(c)
+	case TypeSetAttr:
+		_, attrName := getAttrNameFromSetAttr(&c.Token)
+		// The rest of a skipped array like ".vt[500:999]" is skipped too,
+		// because it can't inherit the type of the skipped value.
+		isRest := s.skippedAttr != "" && isSameAttr(s.skippedAttr, attrName)
+		if isRest || (s.SkipAttrValue != nil && s.SkipAttrValue(s.nodeType, attrName)) {
+			s.beforeSetAttr = nil
+			s.skippedAttr = attrName
+			return &SetAttrCmd{Cmd: c, AttrName: attrName}, nil
+		}
+		s.skippedAttr = ""
+		sa, err := ParseSetAttr(c, s.beforeSetAttr)
+		if err != nil {
+			s.beforeSetAttr = nil
+			return nil, err
+		}
+		s.beforeSetAttr = sa
+		return sa, nil
+	case TypeConnectAttr:
+		s.nodeType = ""
+		s.beforeSetAttr = nil
+		s.skippedAttr = ""
+		return ParseConnectAttr(c)
+	}
+	return c, nil
+}
@@ -0,0 +1,69 @@
+package mayaascii
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestScanner_Next(t *testing.T) {
+	s := NewScanner(strings.NewReader(`//Maya ASCII 2019 scene
+file -r -ns "char" -dr 1 -rfn "charRN" -typ "mayaAscii" "char.ma";
+requires maya "2019";
+createNode mesh -n "meshShape";
+	setAttr -s 4 ".vt[0:1]" 0 0 0
+		1 1 1;
+	setAttr ".vt[2:3]" 2 2 2 3 3 3;
+	setAttr ".v" no;
+connectAttr "a.o" "b.i";
+select -ne :time1;
+	setAttr ".o" 1`))
+	s.SkipAttrValue = func(nodeType, attrName string) bool {
+		return nodeType == "mesh" && strings.HasPrefix(attrName, ".vt")
+	}
+
+	var cmds []Command
+	for {
+		c, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmds = append(cmds, c)
+	}
+	if len(cmds) != 10 {
+		t.Fatalf("got len(cmds) %d, wont %d", len(cmds), 10)
+	}
+	_, ok := cmds[0].(*LineCommentCmd)
+	boolTester(boolTestData{"cmds[0] is LineCommentCmd", ok, true}, t)
+	_, ok = cmds[1].(*FileCmd)
+	boolTester(boolTestData{"cmds[1] is FileCmd", ok, true}, t)
+	_, ok = cmds[2].(*RequiresCmd)
+	boolTester(boolTestData{"cmds[2] is RequiresCmd", ok, true}, t)
+	cn, ok := cmds[3].(*CreateNodeCmd)
+	boolTester(boolTestData{"cmds[3] is CreateNodeCmd", ok, true}, t)
+	stringTester(stringTestData{"cmds[3].NodeName", cn.NodeName, "meshShape"}, t)
+
+	for i, name := range []string{".vt[0:1]", ".vt[2:3]"} {
+		sa, ok := cmds[4+i].(*SetAttrCmd)
+		boolTester(boolTestData{name + " is SetAttrCmd", ok, true}, t)
+		stringTester(stringTestData{name + " AttrName", sa.AttrName, name}, t)
+		intTester(intTestData{name + " len(Attr)", len(sa.Attr), 0}, t)
+	}
+	intTester(intTestData{"cmds[4].LineNo", int(cmds[4].GetCmd().LineNo), 6}, t)
+
+	sa, ok := cmds[6].(*SetAttrCmd)
+	boolTester(boolTestData{".v is SetAttrCmd", ok, true}, t)
+	intTester(intTestData{".v len(Attr)", len(sa.Attr), 1}, t)
+	_, ok = cmds[7].(*ConnectAttrCmd)
+	boolTester(boolTestData{"cmds[7] is ConnectAttrCmd", ok, true}, t)
+	_, ok = cmds[8].(*SelectCmd)
+	boolTester(boolTestData{"cmds[8] is SelectCmd", ok, true}, t)
+
+	// The last command has no ";".
+	sa, ok = cmds[9].(*SetAttrCmd)
+	boolTester(boolTestData{"cmds[9] is SetAttrCmd", ok, true}, t)
+	stringTester(stringTestData{"cmds[9].AttrName", sa.AttrName, ".o"}, t)
+}
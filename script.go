@@ -0,0 +1,333 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ScriptType is the ".st" attribute of scriptNode, when the script runs.
+type ScriptType int
+
+const (
+	ScriptTypeDemand ScriptType = iota
+	ScriptTypeOpenClose
+	ScriptTypeGUIOpenClose
+	ScriptTypeUIConfiguration
+	ScriptTypeSoftwareRender
+	ScriptTypeSoftwareFrameRender
+	// ScriptTypeSceneConfiguration is the internal type of
+	// sceneConfigurationScriptNode that runs on open.
+	ScriptTypeSceneConfiguration
+	ScriptTypeTimeChanged
+)
+
+var scriptTypeNames = map[ScriptType]string{
+	ScriptTypeDemand:              "Demand",
+	ScriptTypeOpenClose:           "Open/Close",
+	ScriptTypeGUIOpenClose:        "GUI Open/Close",
+	ScriptTypeUIConfiguration:     "UI Configuration",
+	ScriptTypeSoftwareRender:      "Software Render",
+	ScriptTypeSoftwareFrameRender: "Software Frame Render",
+	ScriptTypeSceneConfiguration:  "Scene Configuration (Internal)",
+	ScriptTypeTimeChanged:         "Time Changed",
+}
+
+func (t ScriptType) String() string {
+	if name, ok := scriptTypeNames[t]; ok {
+		return name
+	}
+	return "ScriptType(?)"
+}
+
+// RunsOnOpen reports whether the script runs when the scene is opened.
+func (t ScriptType) RunsOnOpen() bool {
+	return t == ScriptTypeOpenClose || t == ScriptTypeGUIOpenClose ||
+		t == ScriptTypeUIConfiguration || t == ScriptTypeSceneConfiguration
+}
+
+// ScriptLanguage is the ".stp" attribute of scriptNode.
+type ScriptLanguage int
+
+const (
+	ScriptLanguageMEL ScriptLanguage = iota
+	ScriptLanguagePython
+)
+
+func (l ScriptLanguage) String() string {
+	if l == ScriptLanguagePython {
+		return "Python"
+	}
+	return "MEL"
+}
+
+// ScriptSignature is a pattern of a script. The pattern is searched in the
+// node name and the sources.
+type ScriptSignature struct {
+	// Name is the name of the malware like "vaccine", or the pattern.
+	Name    string
+	Pattern string
+	// Malicious reports whether the pattern is a known malware, not only
+	// suspicious.
+	Malicious bool
+}
+
+// ScriptSignatures is the signatures of ScanScripts.
+var ScriptSignatures = []ScriptSignature{
+	{Name: "vaccine", Pattern: "vaccine_gene", Malicious: true},
+	{Name: "vaccine", Pattern: "breed_gene", Malicious: true},
+	{Name: "vaccine", Pattern: "leukocyte", Malicious: true},
+	{Name: "vaccine", Pattern: "vaccine.py", Malicious: true},
+	{Name: "PuTianTongQing", Pattern: "PuTianTongQing", Malicious: true},
+	{Name: "PuTianTongQing", Pattern: "MayaMelUIConfigurationFile", Malicious: true},
+	{Name: "exec", Pattern: "exec"},
+	{Name: "base64", Pattern: "base64"},
+	{Name: "userSetup", Pattern: "userSetup"},
+	{Name: "os.system", Pattern: "os.system"},
+	{Name: "subprocess", Pattern: "subprocess"},
+	{Name: "__import__", Pattern: "__import__"},
+}
+
+// ScriptFinding is a signature found in a scriptNode.
+type ScriptFinding struct {
+	Signature ScriptSignature
+	// Attr is the attribute of the source like ".b" and ".before", or ""
+	// for the node name.
+	Attr   string
+	LineNo uint
+}
+
+// ScriptNode is a scriptNode, a node of "script" type, with the decoded
+// sources.
+type ScriptNode struct {
+	Node     *Node
+	Type     ScriptType
+	Language ScriptLanguage
+	// Before is the ".b" script and After is the ".a" script that Maya
+	// runs, the last setAttr of them.
+	Before   string
+	After    string
+	Findings []*ScriptFinding
+
+	// attrs is the setAttrs of the node and of the selects of the node.
+	attrs []*Attr
+}
+
+// IsMalicious reports whether the script has a known malware.
+func (s *ScriptNode) IsMalicious() bool {
+	for _, f := range s.Findings {
+		if f.Signature.Malicious {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuspicious reports whether the script has a signature.
+func (s *ScriptNode) IsSuspicious() bool {
+	return 0 < len(s.Findings)
+}
+
+// GetScriptNodes returns the scriptNodes of o in the order of the file.
+func (o *Object) GetScriptNodes() []*ScriptNode {
+	var scripts []*ScriptNode
+	for _, n := range o.sortedNodes() {
+		if n.isDeleted || n.GetType() != "script" {
+			continue
+		}
+		scripts = append(scripts, o.newScriptNode(n))
+	}
+	return scripts
+}
+
+// GetScriptNode returns the scriptNode of name.
+func (o *Object) GetScriptNode(name string) (*ScriptNode, error) {
+	n, err := o.GetNode(name)
+	if err != nil {
+		return nil, err
+	}
+	if n.isDeleted || n.GetType() != "script" {
+		return nil, errors.New(fmt.Sprintf("%s is not a scriptNode", name))
+	}
+	return o.newScriptNode(n), nil
+}
+
+func (o *Object) newScriptNode(n *Node) *ScriptNode {
+	attrs := o.getNodeAttrs(n)
+	return &ScriptNode{
+		Node:     n,
+		Type:     ScriptType(getIntAttr(attrs, ".st", ".scriptType")),
+		Language: ScriptLanguage(getIntAttr(attrs, ".stp", ".sourceType")),
+		Before:   getStringAttr(attrs, ".b", ".before"),
+		After:    getStringAttr(attrs, ".a", ".after"),
+		attrs:    attrs,
+	}
+}
+
+// ScanScripts returns the scriptNodes of o in the order of the file with
+// the ScriptSignatures found in them.
+func (o *Object) ScanScripts() []*ScriptNode {
+	scripts := o.GetScriptNodes()
+	for _, s := range scripts {
+		s.scan()
+	}
+	return scripts
+}
+
+// scan sets the ScriptSignatures found in s to Findings. Every setAttr of
+// the sources is scanned, not only the last one, and the raw command is
+// scanned if the value is not a string.
+func (s *ScriptNode) scan() {
+	type source struct {
+		attr   string
+		text   string
+		lineNo uint
+	}
+	sources := []source{{"", s.Node.GetName(), s.Node.createNodeCmd.LineNo}}
+	for _, a := range s.attrs {
+		if a.isDeleted {
+			continue
+		}
+		switch a.GetName() {
+		case ".b", ".before", ".a", ".after":
+		default:
+			continue
+		}
+		c := a.getCmd()
+		text := c.Raw
+		if v, ok := getAttrString(a); ok {
+			text = v
+		}
+		sources = append(sources, source{a.GetName(), text, c.LineNo})
+	}
+	s.Findings = nil
+	for _, sig := range ScriptSignatures {
+		for _, src := range sources {
+			if strings.Contains(src.text, sig.Pattern) {
+				s.Findings = append(s.Findings,
+					&ScriptFinding{Signature: sig, Attr: src.attr, LineNo: src.lineNo})
+			}
+		}
+	}
+}
+
+// SanitizeScripts removes the malicious scriptNodes, their connections and
+// the selects of them from o, and the suspicious ones too if suspicious.
+// It returns the removed scriptNodes.
+func (o *Object) SanitizeScripts(suspicious bool) []*ScriptNode {
+	var removed []*ScriptNode
+	for _, s := range o.ScanScripts() {
+		if s.IsMalicious() || (suspicious && s.IsSuspicious()) {
+			o.stripNode(s.Node)
+			o.removeSelects(s.Node)
+			removed = append(removed, s)
+		}
+	}
+	return removed
+}
+
+// getNodeAttrs returns the attributes of n and of the selects of n like
+// "select -ne :n" in the order of the file.
+func (o *Object) getNodeAttrs(n *Node) []*Attr {
+	attrs := append([]*Attr{}, n.Attrs...)
+	for _, s := range o.Selects {
+		if n.isNodePath(s.GetName()) {
+			attrs = append(attrs, s.Attrs...)
+		}
+	}
+	return attrs
+}
+
+// removeSelects removes the selects of n and their attributes.
+func (o *Object) removeSelects(n *Node) {
+	var kept []*Select
+	for _, s := range o.Selects {
+		if !n.isNodePath(s.GetName()) {
+			kept = append(kept, s)
+			continue
+		}
+		o.removeCmd(s.selectCmd.Cmd)
+		for _, a := range s.Attrs {
+			o.removeCmd(a.getCmd())
+		}
+	}
+	o.Selects = kept
+}
+
+// getAttrString returns the unescaped string value of a.
+func getAttrString(a *Attr) (string, bool) {
+	values := a.GetAttrValue()
+	if len(values) == 0 {
+		return "", false
+	}
+	s, ok := values[0].(*AttrString)
+	if !ok {
+		return "", false
+	}
+	return unescapeMayaString(string(*s)), true
+}
+
+// getStringAttr returns the string value of the last setAttr of names in
+// attrs like Maya, or "".
+func getStringAttr(attrs []*Attr, names ...string) string {
+	value := ""
+	for _, a := range getNamedAttrs(attrs, names...) {
+		if v, ok := getAttrString(a); ok {
+			value = v
+		}
+	}
+	return value
+}
+
+// getIntAttr returns the int value of the last setAttr of names in attrs,
+// or 0.
+func getIntAttr(attrs []*Attr, names ...string) int {
+	value := 0
+	for _, a := range getNamedAttrs(attrs, names...) {
+		values := a.GetAttrValue()
+		if len(values) == 0 {
+			continue
+		}
+		if i, ok := values[0].(*AttrInt); ok {
+			value = i.Int()
+		}
+	}
+	return value
+}
+
+// getFloatAttr returns the float value of the last setAttr of names in
+// attrs, or 0.
+func getFloatAttr(attrs []*Attr, names ...string) float64 {
+	value := 0.0
+	for _, a := range getNamedAttrs(attrs, names...) {
+		values := a.GetAttrValue()
+		if len(values) == 0 {
+			continue
+		}
+		switch v := values[0].(type) {
+		case *AttrFloat:
+			value = v.Float()
+		case *AttrInt:
+			value = float64(v.Int())
+		}
+	}
+	return value
+}
+
+// getNamedAttrs returns the attributes of names in attrs that are not
+// deleted.
+func getNamedAttrs(attrs []*Attr, names ...string) []*Attr {
+	var results []*Attr
+	for _, a := range attrs {
+		if a.isDeleted {
+			continue
+		}
+		for _, name := range names {
+			if a.GetName() == name {
+				results = append(results, a)
+				break
+			}
+		}
+	}
+	return results
+}
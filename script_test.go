@@ -0,0 +1,161 @@
+package mayaascii
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const scriptTestMa = `createNode transform -n "pCube1";
+createNode script -n "sceneConfigurationScriptNode";
+	setAttr ".b" -type "string" "playbackOptions -min 1 -max 120";
+	setAttr ".st" 6;
+createNode script -n "vaccine_gene";
+	setAttr ".b" -type "string" ("import vaccine\n" + "cmds.evalDeferred('leukocyte = vaccine.phage()')");
+	setAttr ".st" 1;
+	setAttr ".stp" 1;
+createNode script -n "loader";
+	setAttr ".b" -type "string" "python(\"import os;os.system('ls')\")";
+	setAttr ".st" 2;
+connectAttr "vaccine_gene.msg" "pCube1.bnm";
+`
+
+func TestObject_ScanScripts(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(scriptTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts := o.ScanScripts()
+	intTester(intTestData{"len", len(scripts), 3}, t)
+
+	s := scripts[0]
+	intTester(intTestData{"Type", int(s.Type), int(ScriptTypeSceneConfiguration)}, t)
+	stringTester(stringTestData{"Type", s.Type.String(), "Scene Configuration (Internal)"}, t)
+	stringTester(stringTestData{"Language", s.Language.String(), "MEL"}, t)
+	boolTester(boolTestData{"RunsOnOpen", s.Type.RunsOnOpen(), true}, t)
+	boolTester(boolTestData{"IsSuspicious", s.IsSuspicious(), false}, t)
+
+	s = scripts[1]
+	stringTester(stringTestData{"Type", s.Type.String(), "Open/Close"}, t)
+	stringTester(stringTestData{"Language", s.Language.String(), "Python"}, t)
+	stringTester(stringTestData{"Before", s.Before,
+		"import vaccine\ncmds.evalDeferred('leukocyte = vaccine.phage()')"}, t)
+	boolTester(boolTestData{"RunsOnOpen", s.Type.RunsOnOpen(), true}, t)
+	boolTester(boolTestData{"IsMalicious", s.IsMalicious(), true}, t)
+
+	s = scripts[2]
+	stringTester(stringTestData{"Before", s.Before, "python(\"import os;os.system('ls')\")"}, t)
+	boolTester(boolTestData{"IsMalicious", s.IsMalicious(), false}, t)
+	boolTester(boolTestData{"IsSuspicious", s.IsSuspicious(), true}, t)
+	intTester(intTestData{"Findings", len(s.Findings), 1}, t)
+	stringTester(stringTestData{"Finding", s.Findings[0].Signature.Name, "os.system"}, t)
+	stringTester(stringTestData{"Finding Attr", s.Findings[0].Attr, ".b"}, t)
+}
+
+func TestObject_SanitizeScripts(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(scriptTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := o.SanitizeScripts(false)
+	intTester(intTestData{"removed", len(removed), 1}, t)
+	stringTester(stringTestData{"removed name", removed[0].Node.GetName(), "vaccine_gene"}, t)
+	intTester(intTestData{"scripts", len(o.ScanScripts()), 2}, t)
+
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"vaccine", strings.Contains(b.String(), "vaccine"), false}, t)
+
+	removed = o.SanitizeScripts(true)
+	intTester(intTestData{"removed suspicious", len(removed), 1}, t)
+	stringTester(stringTestData{"removed suspicious name", removed[0].Node.GetName(), "loader"}, t)
+	intTester(intTestData{"scripts", len(o.ScanScripts()), 1}, t)
+}
+
+func TestObject_GetScriptNode(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(scriptTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"len", len(o.GetScriptNodes()), 3}, t)
+	s, err := o.GetScriptNode("sceneConfigurationScriptNode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Before", s.Before, "playbackOptions -min 1 -max 120"}, t)
+	stringTester(stringTestData{"After", s.After, ""}, t)
+	stringTester(stringTestData{"Type", s.Type.String(), "Scene Configuration (Internal)"}, t)
+	intTester(intTestData{"Findings", len(s.Findings), 0}, t)
+	if _, err := o.GetScriptNode("pCube1"); err == nil {
+		t.Errorf("GetScriptNode(pCube1) got nil error")
+	}
+}
+
+func TestObject_ScanScripts_repeatedSetAttr(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode script -n "loader";
+	setAttr ".b" -type "string" "python(\"import os;os.system('ls')\")";
+	setAttr ".before" -type "string" "print 1";
+	setAttr ".st" 0;
+	setAttr ".scriptType" 2;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := o.ScanScripts()[0]
+	stringTester(stringTestData{"Before", s.Before, "print 1"}, t)
+	stringTester(stringTestData{"Type", s.Type.String(), "GUI Open/Close"}, t)
+	intTester(intTestData{"Findings", len(s.Findings), 1}, t)
+	stringTester(stringTestData{"Finding Attr", s.Findings[0].Attr, ".b"}, t)
+	intTester(intTestData{"Finding LineNo", int(s.Findings[0].LineNo), 2}, t)
+}
+
+func TestObject_ScanScripts_select(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`createNode transform -n "pCube1";
+createNode script -n "loader";
+	setAttr ".b" -type "string" "print 1";
+select -ne :loader;
+	setAttr ".a" -type "string" "python(\"import os;os.system('ls')\")";
+	setAttr ".st" 1;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := o.ScanScripts()[0]
+	stringTester(stringTestData{"Before", s.Before, "print 1"}, t)
+	stringTester(stringTestData{"After", s.After, "python(\"import os;os.system('ls')\")"}, t)
+	stringTester(stringTestData{"Type", s.Type.String(), "Open/Close"}, t)
+	intTester(intTestData{"Findings", len(s.Findings), 1}, t)
+	stringTester(stringTestData{"Finding Attr", s.Findings[0].Attr, ".a"}, t)
+
+	removed := o.SanitizeScripts(true)
+	intTester(intTestData{"removed", len(removed), 1}, t)
+	intTester(intTestData{"Selects", len(o.Selects), 0}, t)
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	boolTester(boolTestData{"loader", strings.Contains(b.String(), "loader"), false}, t)
+	boolTester(boolTestData{"os.system", strings.Contains(b.String(), "os.system"), false}, t)
+}
+
+func TestScriptType(t *testing.T) {
+	for _, d := range []struct {
+		st         int
+		name       string
+		runsOnOpen bool
+	}{
+		{0, "Demand", false},
+		{1, "Open/Close", true},
+		{2, "GUI Open/Close", true},
+		{3, "UI Configuration", true},
+		{4, "Software Render", false},
+		{5, "Software Frame Render", false},
+		{6, "Scene Configuration (Internal)", true},
+		{7, "Time Changed", false},
+	} {
+		stringTester(stringTestData{"String", ScriptType(d.st).String(), d.name}, t)
+		boolTester(boolTestData{d.name, ScriptType(d.st).RunsOnOpen(), d.runsOnOpen}, t)
+	}
+}
@@ -0,0 +1,288 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LocalXform returns the transformation attributes of n, a transform or a
+// joint. The attributes without setAttr are Maya's defaults, so the nodes
+// without them like shapes are identity. Rotate is in radians and the
+// rotate axis and the joint orient are quaternions like the xform data.
+func (n *Node) LocalXform() (*AttrMatrixXform, error) {
+	return n.getLocalXform(getNodeAttrFloats)
+}
+
+// getLocalXform returns the transformation attributes of n with the values
+// of get like getNodeAttrFloats.
+func (n *Node) getLocalXform(get attrFloatsGetter) (*AttrMatrixXform, error) {
+	toRadian := math.Pi / 180
+	if n.object != nil && n.object.GetCurrentUnit().Angle == "radian" {
+		toRadian = 1
+	}
+	ro := 0
+	if values := get(n, ".ro"); 0 < len(values) {
+		ro = int(values[0])
+	}
+	if ro < 0 || len(rotateOrderList) <= ro {
+		return nil, errors.New(
+			fmt.Sprintf("%s has an invalid rotate order %d", n.GetName(), ro))
+	}
+	rotateOrder := rotateOrderList[ro]
+	rotate := getNodeVector(get, n, "r", 0)
+	shear := getNodeVector(get, n, "sh", 0)
+	amx := &AttrMatrixXform{
+		Scale: getNodeVector(get, n, "s", 1),
+		Rotate: AttrVector{
+			X: rotate.X * toRadian, Y: rotate.Y * toRadian, Z: rotate.Z * toRadian},
+		RotateOrder:        rotateOrder,
+		Translate:          getNodeVector(get, n, "t", 0),
+		Shear:              AttrShear{XY: shear.X, XZ: shear.Y, YZ: shear.Z},
+		ScalePivot:         getNodeVector(get, n, "sp", 0),
+		ScaleTranslate:     getNodeVector(get, n, "spt", 0),
+		RotatePivot:        getNodeVector(get, n, "rp", 0),
+		RotateTranslation:  getNodeVector(get, n, "rpt", 0),
+		RotateOrient:       getEulerOrient(getNodeVector(get, n, "ra", 0), toRadian),
+		JointOrient:        getEulerOrient(getNodeVector(get, n, "jo", 0), toRadian),
+		InverseParentScale: AttrVector{X: 1, Y: 1, Z: 1},
+	}
+	// The segment scale compensate of a joint cancels the scale of the
+	// parent joint.
+	if n.GetType() == "joint" && n.Parent != nil && n.Parent.GetType() == "joint" {
+		amx.CompensateForParentScale = true
+		if values := get(n, ".ssc"); 0 < len(values) {
+			amx.CompensateForParentScale = values[0] != 0
+		}
+		amx.InverseParentScale = getNodeVector(get, n.Parent, "s", 1)
+	}
+	return amx, nil
+}
+
+// LocalMatrix returns the matrix of n in the space of the parent.
+func (n *Node) LocalMatrix() (*AttrMatrix, error) {
+	amx, err := n.LocalXform()
+	if err != nil {
+		return nil, err
+	}
+	m := amx.Matrix()
+	return &m, nil
+}
+
+// WorldMatrix returns the matrix of n in the world space, the local
+// matrices of n and its parents. The parents of a node with ".it" off, the
+// inheritsTransform, are not multiplied.
+func (n *Node) WorldMatrix() (*AttrMatrix, error) {
+	return n.getWorldMatrix(getNodeAttrFloats)
+}
+
+// getWorldMatrix returns the matrix of n in the world space with the
+// values of get like getNodeAttrFloats.
+func (n *Node) getWorldMatrix(get attrFloatsGetter) (*AttrMatrix, error) {
+	m := identityMatrix()
+	for p := n; p != nil; p = p.Parent {
+		amx, err := p.getLocalXform(get)
+		if err != nil {
+			return nil, err
+		}
+		m = multiplyMatrix(m, amx.Matrix())
+		if values := get(p, ".it"); 0 < len(values) && values[0] == 0 {
+			break
+		}
+	}
+	return &m, nil
+}
+
+// Matrix returns the matrix of amx by Maya's formula for the row vectors
+//
+//	SP^-1 * S * SH * SP * ST * RP^-1 * RA * R * JO * RP * RT * IS * T
+//
+// where IS is the inverse parent scale of CompensateForParentScale.
+func (amx *AttrMatrixXform) Matrix() AttrMatrix {
+	sp, rp := amx.ScalePivot, amx.RotatePivot
+	m := translateMatrix(AttrVector{X: -sp.X, Y: -sp.Y, Z: -sp.Z})
+	m = multiplyMatrix(m, scaleMatrix(amx.Scale))
+	m = multiplyMatrix(m, shearMatrix(amx.Shear))
+	m = multiplyMatrix(m, translateMatrix(sp))
+	m = multiplyMatrix(m, translateMatrix(amx.ScaleTranslate))
+	m = multiplyMatrix(m, translateMatrix(AttrVector{X: -rp.X, Y: -rp.Y, Z: -rp.Z}))
+	m = multiplyMatrix(m, orientMatrix(amx.RotateOrient))
+	m = multiplyMatrix(m, rotateMatrix(amx.Rotate, amx.RotateOrder))
+	m = multiplyMatrix(m, orientMatrix(amx.JointOrient))
+	m = multiplyMatrix(m, translateMatrix(rp))
+	m = multiplyMatrix(m, translateMatrix(amx.RotateTranslation))
+	if amx.CompensateForParentScale {
+		inverse := func(f float64) float64 {
+			if f == 0 {
+				return 1
+			}
+			return 1 / f
+		}
+		ips := amx.InverseParentScale
+		m = multiplyMatrix(m, scaleMatrix(
+			AttrVector{X: inverse(ips.X), Y: inverse(ips.Y), Z: inverse(ips.Z)}))
+	}
+	return multiplyMatrix(m, translateMatrix(amx.Translate))
+}
+
+// attrFloatsGetter returns the numbers of the attribute name like ".tx"
+// of n.
+type attrFloatsGetter func(n *Node, name string) []float64
+
+// getNodeVector returns the compound like "t" of n, or its children like
+// "tx", or def.
+func getNodeVector(get attrFloatsGetter, n *Node, compound string, def float64) AttrVector {
+	v := [3]float64{def, def, def}
+	if values := get(n, "."+compound); 3 <= len(values) {
+		copy(v[:], values)
+	}
+	children := [3]string{compound + "x", compound + "y", compound + "z"}
+	if compound == "sh" {
+		children = [3]string{"shxy", "shxz", "shyz"}
+	}
+	for i, child := range children {
+		if values := get(n, "."+child); 0 < len(values) {
+			v[i] = values[0]
+		}
+	}
+	return AttrVector{X: v[0], Y: v[1], Z: v[2]}
+}
+
+// getEulerOrient returns the quaternion of the XYZ rotation e.
+func getEulerOrient(e AttrVector, toRadian float64) AttrOrient {
+	half := func(f float64) (float64, float64) {
+		return math.Sin(f * toRadian / 2), math.Cos(f * toRadian / 2)
+	}
+	sx, cx := half(e.X)
+	sy, cy := half(e.Y)
+	sz, cz := half(e.Z)
+	// Z * Y * X of the quaternions rotates X first.
+	return AttrOrient{
+		W: cz*cy*cx + sz*sy*sx,
+		X: cz*cy*sx - sz*sy*cx,
+		Y: cz*sy*cx + sz*cy*sx,
+		Z: sz*cy*cx - cz*sy*sx,
+	}
+}
+
+func identityMatrix() AttrMatrix {
+	return AttrMatrix{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func multiplyMatrix(a, b AttrMatrix) AttrMatrix {
+	var m AttrMatrix
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			for i := 0; i < 4; i++ {
+				m[r*4+c] += a[r*4+i] * b[i*4+c]
+			}
+		}
+	}
+	return m
+}
+
+func translateMatrix(v AttrVector) AttrMatrix {
+	m := identityMatrix()
+	m[12], m[13], m[14] = v.X, v.Y, v.Z
+	return m
+}
+
+func scaleMatrix(v AttrVector) AttrMatrix {
+	m := identityMatrix()
+	m[0], m[5], m[10] = v.X, v.Y, v.Z
+	return m
+}
+
+func shearMatrix(sh AttrShear) AttrMatrix {
+	m := identityMatrix()
+	m[4], m[8], m[9] = sh.XY, sh.XZ, sh.YZ
+	return m
+}
+
+// rotateMatrix returns the matrix of the rotation r in radians of the
+// order like XYZ, X first.
+func rotateMatrix(r AttrVector, order AttrRotateOrder) AttrMatrix {
+	axis := func(a float64, i, j int) AttrMatrix {
+		m := identityMatrix()
+		s, c := math.Sin(a), math.Cos(a)
+		m[i*4+i], m[i*4+j] = c, s
+		m[j*4+i], m[j*4+j] = -s, c
+		return m
+	}
+	x, y, z := axis(r.X, 1, 2), axis(r.Y, 2, 0), axis(r.Z, 0, 1)
+	var ms [3]AttrMatrix
+	switch order {
+	case RotateOrderYZX:
+		ms = [3]AttrMatrix{y, z, x}
+	case RotateOrderZXY:
+		ms = [3]AttrMatrix{z, x, y}
+	case RotateOrderXZY:
+		ms = [3]AttrMatrix{x, z, y}
+	case RotateOrderYXZ:
+		ms = [3]AttrMatrix{y, x, z}
+	case RotateOrderZYX:
+		ms = [3]AttrMatrix{z, y, x}
+	default:
+		ms = [3]AttrMatrix{x, y, z}
+	}
+	return multiplyMatrix(multiplyMatrix(ms[0], ms[1]), ms[2])
+}
+
+// getEulerRotation returns the rotation in radians of the order like XYZ,
+// X first, of the matrix m without the scale.
+func getEulerRotation(m AttrMatrix, order AttrRotateOrder) AttrVector {
+	// r[i][j] is the row-major rotation of the column vectors, the
+	// transpose of m, of the normalized axes.
+	var r [3][3]float64
+	for i := 0; i < 3; i++ {
+		length := math.Sqrt(m[i*4]*m[i*4] + m[i*4+1]*m[i*4+1] + m[i*4+2]*m[i*4+2])
+		if length == 0 {
+			length = 1
+		}
+		for j := 0; j < 3; j++ {
+			r[j][i] = m[i*4+j] / length
+		}
+	}
+	// i is the first axis, j the second and k the last.
+	var i, j, k int
+	switch order {
+	case RotateOrderYZX:
+		i, j, k = 1, 2, 0
+	case RotateOrderZXY:
+		i, j, k = 2, 0, 1
+	case RotateOrderXZY:
+		i, j, k = 0, 2, 1
+	case RotateOrderYXZ:
+		i, j, k = 1, 0, 2
+	case RotateOrderZYX:
+		i, j, k = 2, 1, 0
+	default:
+		i, j, k = 0, 1, 2
+	}
+	// The odd permutations of XYZ have the opposite signs.
+	sign := 1.0
+	if (j-i+3)%3 != 1 {
+		sign = -1
+	}
+	var angles [3]float64
+	angles[j] = math.Asin(math.Max(-1, math.Min(1, -sign*r[k][i])))
+	angles[i] = math.Atan2(sign*r[k][j], r[k][k])
+	angles[k] = math.Atan2(sign*r[j][i], r[i][i])
+	return AttrVector{X: angles[0], Y: angles[1], Z: angles[2]}
+}
+
+// orientMatrix returns the matrix of the quaternion q.
+func orientMatrix(q AttrOrient) AttrMatrix {
+	x, y, z, w := q.X, q.Y, q.Z, q.W
+	return AttrMatrix{
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w), 0,
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w), 0,
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	}
+}
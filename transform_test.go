@@ -0,0 +1,164 @@
+package mayaascii
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func matrixTester(title string, m *AttrMatrix, wont AttrMatrix, t *testing.T) {
+	for i := range wont {
+		floatTester(floatTestData{fmt.Sprintf("%s[%d]", title, i), m[i], wont[i]}, t)
+	}
+}
+
+const transformTestMa = `createNode transform -n "parent";
+	setAttr ".t" -type "double3" 10 0 0 ;
+	setAttr ".r" -type "double3" 0 0 90 ;
+createNode transform -n "child" -p "parent";
+	setAttr ".tx" 1;
+createNode transform -n "order";
+	setAttr ".r" -type "double3" 90 0 90 ;
+	setAttr ".ro" 2;
+createNode transform -n "scalePivot";
+	setAttr ".s" -type "double3" 2 2 2 ;
+	setAttr ".sp" -type "double3" 1 0 0 ;
+createNode transform -n "rotatePivot";
+	setAttr ".rz" 90;
+	setAttr ".rp" -type "double3" 1 0 0 ;
+	setAttr ".rpt" -type "double3" 0 0 5 ;
+createNode transform -n "shear";
+	setAttr ".sh" -type "double3" 1 0 0 ;
+createNode transform -n "rotateAxis";
+	setAttr ".ra" -type "double3" 0 0 90 ;
+createNode joint -n "joint1";
+	setAttr ".s" -type "double3" 2 2 2 ;
+createNode joint -n "joint2" -p "joint1";
+	setAttr ".t" -type "double3" 1 0 0 ;
+	setAttr ".jo" -type "double3" 0 0 90 ;
+createNode joint -n "joint3" -p "joint1";
+	setAttr ".ssc" no;
+	setAttr ".jo" -type "double3" 0 0 90 ;
+createNode mesh -n "childShape" -p "child";
+createNode transform -n "noInherit" -p "parent";
+	setAttr ".tx" 1;
+	setAttr ".it" no;
+createNode transform -n "noInheritChild" -p "noInherit";
+	setAttr ".ty" 1;
+`
+
+func TestNode_LocalMatrix(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(transformTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []struct {
+		node string
+		wont AttrMatrix
+	}{
+		{"parent", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 10, 0, 0, 1}},
+		{"child", AttrMatrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 0, 1}},
+		{"order", AttrMatrix{0, 0, 1, 0, -1, 0, 0, 0, 0, -1, 0, 0, 0, 0, 0, 1}},
+		{"scalePivot", AttrMatrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, -1, 0, 0, 1}},
+		{"rotatePivot", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 1, -1, 5, 1}},
+		{"shear", AttrMatrix{1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+		{"rotateAxis", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+		{"joint2", AttrMatrix{0, 0.5, 0, 0, -0.5, 0, 0, 0, 0, 0, 0.5, 0, 1, 0, 0, 1}},
+		{"joint3", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+		{"childShape", identityMatrix()},
+	} {
+		n, err := o.GetNode(d.node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := n.LocalMatrix()
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixTester(d.node, m, d.wont, t)
+	}
+}
+
+func TestNode_WorldMatrix(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(transformTestMa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []struct {
+		node string
+		wont AttrMatrix
+	}{
+		{"child", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 10, 1, 0, 1}},
+		{"childShape", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 10, 1, 0, 1}},
+		{"joint2", AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 2, 0, 0, 1}},
+		{"joint3", AttrMatrix{0, 2, 0, 0, -2, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1}},
+		{"noInherit", AttrMatrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 0, 1}},
+		{"noInheritChild", AttrMatrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 1, 0, 1}},
+	} {
+		n, err := o.GetNode(d.node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := n.WorldMatrix()
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrixTester(d.node, m, d.wont, t)
+	}
+}
+
+func TestNode_LocalXform(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`currentUnit -a radian;
+createNode transform -n "a";
+	setAttr ".r" -type "double3" 0 0 1.5707963267948966 ;
+	setAttr ".ro" 6;
+createNode transform -n "negative";
+	setAttr ".ro" -1;
+createNode transform -n "b";
+	setAttr ".r" -type "double3" 0 0 1.5707963267948966 ;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := o.GetNode("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.LocalXform(); err == nil {
+		t.Errorf("LocalXform() of invalid rotate order got nil error")
+	}
+	n, err = o.GetNode("negative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.LocalXform(); err == nil {
+		t.Errorf("LocalXform() of negative rotate order got nil error")
+	}
+	n, err = o.GetNode("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	amx, err := n.LocalXform()
+	if err != nil {
+		t.Fatal(err)
+	}
+	floatTester(floatTestData{"Rotate.Z", amx.Rotate.Z, math.Pi / 2}, t)
+	floatTester(floatTestData{"Scale.X", amx.Scale.X, 1}, t)
+	floatTester(floatTestData{"JointOrient.W", amx.JointOrient.W, 1}, t)
+	boolTester(boolTestData{"CompensateForParentScale", amx.CompensateForParentScale, false}, t)
+	m := amx.Matrix()
+	matrixTester("b", &m, AttrMatrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}, t)
+}
+
+func TestGetEulerRotation(t *testing.T) {
+	r := AttrVector{X: 0.3, Y: -0.7, Z: 1.2}
+	for ro := 0; ro < 6; ro++ {
+		order := rotateOrderList[ro]
+		m := multiplyMatrix(scaleMatrix(AttrVector{X: 2, Y: 3, Z: 4}), rotateMatrix(r, order))
+		e := getEulerRotation(m, order)
+		floatTester(floatTestData{fmt.Sprintf("%d X", ro), e.X, r.X}, t)
+		floatTester(floatTestData{fmt.Sprintf("%d Y", ro), e.Y, r.Y}, t)
+		floatTester(floatTestData{fmt.Sprintf("%d Z", ro), e.Z, r.Z}, t)
+	}
+}
@@ -0,0 +1,257 @@
+package mayaascii
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionFeature is a node type, an attribute or a flag that was added in
+// a version of Maya.
+type VersionFeature struct {
+	// Version is the version of Maya that added the feature like "2016".
+	Version string
+	// Command and Flag is a flag of a command like "rename" "-uid".
+	Command string
+	Flag    string
+	// RemoveCmd reports whether the command is removed with the flag,
+	// because the command means another thing without the flag.
+	RemoveCmd bool
+	// NodeType is a node type, or the node type of Attr.
+	NodeType string
+	// Attr is the short name of an attribute of NodeType like "dsm".
+	Attr string
+}
+
+func (f VersionFeature) String() string {
+	switch {
+	case f.Flag != "":
+		return fmt.Sprintf("%s %s (Maya %s)", f.Command, f.Flag, f.Version)
+	case f.Attr != "":
+		return fmt.Sprintf("%s.%s (Maya %s)", f.NodeType, f.Attr, f.Version)
+	}
+	return fmt.Sprintf("%s (Maya %s)", f.NodeType, f.Version)
+}
+
+// DefaultVersionTable is the features that TargetVersion checks. It is
+// not complete; append the features of the pipeline to it.
+var DefaultVersionTable = []VersionFeature{
+	{Version: "2016", Command: "rename", Flag: "-uid", RemoveCmd: true},
+	{Version: "2016", Command: "createNode", Flag: "-ss"},
+	{Version: "2016.5", NodeType: "shapeEditorManager"},
+	{Version: "2016.5", NodeType: "poseInterpolatorManager"},
+	{Version: "2017", NodeType: "timeEditor"},
+	{Version: "2017", NodeType: "timeEditorTracks"},
+}
+
+// Incompatibility is a feature of the scene that the target version of
+// Maya doesn't support.
+type Incompatibility struct {
+	Feature VersionFeature
+	// Node is the name of the node of the feature.
+	Node   string
+	LineNo uint
+}
+
+func (i *Incompatibility) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.LineNo, i.Node, i.Feature)
+}
+
+// compareMayaVersion compares the versions like "2016" and "2016.5".
+func compareMayaVersion(a, b string) (int, error) {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var av, bv int
+		var err error
+		if i < len(as) {
+			if av, err = strconv.Atoi(as[i]); err != nil {
+				return 0, errors.New(fmt.Sprintf("invalid Maya version %s", a))
+			}
+		}
+		if i < len(bs) {
+			if bv, err = strconv.Atoi(bs[i]); err != nil {
+				return 0, errors.New(fmt.Sprintf("invalid Maya version %s", b))
+			}
+		}
+		if av != bv {
+			if av < bv {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// getUnsupportedFeatures returns the features of table that version
+// doesn't support.
+func getUnsupportedFeatures(version string, table []VersionFeature) ([]VersionFeature, error) {
+	var features []VersionFeature
+	for _, f := range table {
+		c, err := compareMayaVersion(version, f.Version)
+		if err != nil {
+			return nil, err
+		}
+		if c < 0 {
+			features = append(features, f)
+		}
+	}
+	return features, nil
+}
+
+// CheckVersion returns the features of the scene in DefaultVersionTable
+// that version of Maya doesn't support.
+func (o *Object) CheckVersion(version string) ([]*Incompatibility, error) {
+	return o.checkVersion(version, DefaultVersionTable, false)
+}
+
+// TargetVersion rewrites the scene for version of Maya like "2018". It
+// rewrites "requires maya", the fileInfo "version" and "product", and the
+// "//Maya ASCII" header, and strips the features in DefaultVersionTable
+// that version doesn't support. It returns the stripped features.
+func (o *Object) TargetVersion(version string) ([]*Incompatibility, error) {
+	return o.TargetVersionWithTable(version, DefaultVersionTable)
+}
+
+// TargetVersionWithTable is TargetVersion with the features of table.
+func (o *Object) TargetVersionWithTable(version string, table []VersionFeature) ([]*Incompatibility, error) {
+	incompatibilities, err := o.checkVersion(version, table, true)
+	if err != nil {
+		return nil, err
+	}
+	if rq, err := o.GetRequire("maya"); err == nil {
+		rq.requireCmd.replaceToken(
+			"\""+rq.requireCmd.Version+"\"", "\""+version+"\"")
+		rq.requireCmd.Version = version
+	}
+	h := o.Header()
+	h.MayaVersion = version
+	o.SetHeader(h)
+	if _, err := o.GetFileInfo(FileInfoProduct); err == nil {
+		o.SetFileInfo(FileInfoProduct, "Maya "+version)
+	}
+	return incompatibilities, nil
+}
+
+// checkVersion returns the features of the scene in table that version
+// doesn't support, and strips them if strip.
+func (o *Object) checkVersion(version string, table []VersionFeature, strip bool) ([]*Incompatibility, error) {
+	features, err := getUnsupportedFeatures(version, table)
+	if err != nil {
+		return nil, err
+	}
+	var incompatibilities []*Incompatibility
+	report := func(f VersionFeature, node string, c *Cmd) {
+		incompatibilities = append(incompatibilities,
+			&Incompatibility{Feature: f, Node: node, LineNo: c.LineNo})
+	}
+	for _, n := range o.sortedNodes() {
+		for _, f := range features {
+			if n.isDeleted {
+				// Stripped with the parent or by the previous feature.
+				break
+			}
+			switch {
+			case f.Flag != "":
+				c := n.getFlagCmd(f.Command)
+				if c == nil || !hasToken(c, f.Flag) {
+					continue
+				}
+				report(f, n.GetName(), c)
+				if strip {
+					n.stripFlag(c, f)
+				}
+			case f.NodeType != n.GetType():
+			case f.Attr == "":
+				report(f, n.GetName(), n.createNodeCmd.Cmd)
+				if strip {
+					o.stripNode(n)
+				}
+			default:
+				for _, a := range n.Attrs {
+					if a.isDeleted || getAttrShortName(a.GetName()) != f.Attr {
+						continue
+					}
+					report(f, n.GetName(), a.getCmd())
+					if strip {
+						a.Remove()
+					}
+				}
+			}
+		}
+	}
+	return incompatibilities, nil
+}
+
+// getFlagCmd returns the command of n like "createNode" and "rename".
+func (n *Node) getFlagCmd(command string) *Cmd {
+	switch {
+	case command == "createNode":
+		return n.createNodeCmd.Cmd
+	case command == "rename" && n.renameCmd != nil:
+		return n.renameCmd.Cmd
+	}
+	return nil
+}
+
+func hasToken(c *Cmd, token string) bool {
+	for _, t := range c.Token[1:] {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// stripFlag removes the flag of f from c, or removes c if f.RemoveCmd.
+func (n *Node) stripFlag(c *Cmd, f VersionFeature) {
+	if f.RemoveCmd {
+		n.object.removeCmd(c)
+		if n.renameCmd != nil && n.renameCmd.Cmd == c {
+			n.renameCmd = nil
+		}
+		return
+	}
+	c.removeSwitch(f.Flag)
+	if f.Command == "createNode" {
+		if cn, err := ParseCreateNode(c); err == nil {
+			*n.createNodeCmd = *cn
+		}
+	}
+}
+
+// stripNode removes n with its children and the connections of them.
+func (o *Object) stripNode(n *Node) {
+	stripped := map[*Node]bool{}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		stripped[n] = true
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	var kept []*ConnectAttrCmd
+	for _, ca := range o.connections.source {
+		// The plugs may have the DAG paths like "|grp|te.msg".
+		src := o.findNodeByPath(ca.SrcNode, false)
+		dst := o.findNodeByPath(ca.DstNode, false)
+		if stripped[src] || stripped[dst] {
+			o.removeCmd(ca.Cmd)
+			continue
+		}
+		kept = append(kept, ca)
+	}
+	o.connections.source = kept
+	n.Remove()
+}
+
+// getAttrShortName returns "vt" of ".vt[0:3]".
+func getAttrShortName(name string) string {
+	name = strings.TrimPrefix(name, ".")
+	if i := strings.IndexAny(name, "[."); i != -1 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,139 @@
+package mayaascii
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func getVersionTestMa() string {
+	return `//Maya ASCII 2019 scene
+//Codeset: UTF-8
+requires maya "2019";
+fileInfo "product" "Maya 2019";
+fileInfo "version" "2019";
+createNode transform -s -n "persp";
+	rename -uid "CFAE1109-4845-2AC4-5BC0-CB8FB886A568";
+	setAttr ".v" no;
+createNode shapeEditorManager -ss -n "shapeEditorManager";
+createNode timeEditor -s -n "timeEditor";
+createNode mesh -ss -n "m" -p "persp";
+	setAttr ".dsm" 2;
+	setAttr ".vt[0:1]" 0 0 0 1 1 1;
+connectAttr "timeEditor.msg" "persp.msg";
+connectAttr "m.w" "persp.v";
+`
+}
+
+func TestCompareMayaVersion(t *testing.T) {
+	for _, d := range []struct {
+		a, b string
+		wont int
+	}{
+		{"2016", "2016", 0},
+		{"2016", "2016.5", -1},
+		{"2017", "2016.5", 1},
+		{"2018", "2022", -1},
+	} {
+		c, err := compareMayaVersion(d.a, d.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intTester(intTestData{d.a + " " + d.b, c, d.wont}, t)
+	}
+	if _, err := compareMayaVersion("2018", "next"); err == nil {
+		t.Error("invalid version wont error")
+	}
+}
+
+func TestObject_CheckVersion(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getVersionTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	incompatibilities, err := o.CheckVersion("2016")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range incompatibilities {
+		got = append(got, i.String())
+	}
+	stringTester(stringTestData{"2016", strings.Join(got, "\n"),
+		"line 9: shapeEditorManager: shapeEditorManager (Maya 2016.5)\n" +
+			"line 10: timeEditor: timeEditor (Maya 2017)"}, t)
+
+	incompatibilities, err = o.CheckVersion("2015")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"2015", len(incompatibilities), 5}, t)
+	intTester(intTestData{"Nodes", len(o.Nodes), 4}, t)
+}
+
+func TestObject_TargetVersion(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(getVersionTestMa()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := append([]VersionFeature{{Version: "2020", NodeType: "mesh", Attr: "dsm"}},
+		DefaultVersionTable...)
+	incompatibilities, err := o.TargetVersionWithTable("2015", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intTester(intTestData{"incompatibilities", len(incompatibilities), 6}, t)
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Marshal", b.String(), `//Maya ASCII 2015 scene
+//Codeset: UTF-8
+requires maya "2015";
+fileInfo "product" "Maya 2015";
+fileInfo "version" "2015";
+createNode transform -s -n "persp";
+	setAttr ".v" no;
+createNode mesh -n "m" -p "persp";
+	setAttr ".vt[0:1]" 0 0 0 1 1 1;
+connectAttr "m.w" "persp.v";
+`}, t)
+	boolTester(boolTestData{"SkipSelect", o.Nodes["m"].createNodeCmd.SkipSelect, false}, t)
+	_, err = o.GetNode("persp")
+	boolTester(boolTestData{"persp", err == nil, true}, t)
+	_, err = o.Nodes["persp"].GetUUID()
+	boolTester(boolTestData{"UUID", err != nil, true}, t)
+
+	if _, err := o.TargetVersion("next"); err == nil {
+		t.Error("invalid version wont error")
+	}
+}
+
+func TestObject_TargetVersion_path(t *testing.T) {
+	o, err := Unmarshal(strings.NewReader(`//Maya ASCII 2019 scene
+requires maya "2019";
+createNode transform -n "grp";
+createNode timeEditor -n "te" -p "grp";
+createNode transform -n "x";
+connectAttr "|grp|te.msg" "x.msg";
+connectAttr "grp.v" "x.v";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.TargetVersion("2016"); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := o.Marshal(&b); err != nil {
+		t.Fatal(err)
+	}
+	stringTester(stringTestData{"Marshal", b.String(), `//Maya ASCII 2016 scene
+requires maya "2016";
+fileInfo "version" "2016";
+createNode transform -n "grp";
+createNode transform -n "x";
+connectAttr "grp.v" "x.v";
+`}, t)
+	intTester(intTestData{"connections", len(o.connections.source), 1}, t)
+}